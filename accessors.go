@@ -0,0 +1,252 @@
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// maxNameWords bounds accessor names derived from message texts.
+const maxNameWords = 5
+
+// reTag matches HTML tags, which are left out of accessor names.
+var reTag = regexp.MustCompile(`<[^>]*>`)
+
+var accessorsTemplate = template.Must(template.New("accessors").Parse(`// Code generated by i18n accessors. DO NOT EDIT.
+
+package {{ .Pkg }}
+
+import (
+{{- if .HTML }}
+	"html/template"
+{{ end }}
+	"golang.org/x/text/language"
+
+	"github.com/gowool/i18n"
+)
+{{ range .Funcs }}
+// {{ .Name }} translates {{ printf "%q" .Source }}{{ with .Domain }} in the domain {{ . }}{{ end }}.
+func {{ .Name }}(tag language.Tag{{ range .Params }}, {{ .Name }} {{ .Type }}{{ end }}) {{ if .HTML }}template.HTML{{ else }}string{{ end }} {
+	return i18n.{{ if .HTML }}Thtml{{ else }}T{{ end }}{{ with .Domain }}Domain({{ printf "%q" . }}, {{ else }}({{ end }}tag{{ range .Args }}, {{ . }}{{ end }})
+}
+{{ end }}`))
+
+type (
+	accessorsData struct {
+		Pkg   string
+		HTML  bool
+		Funcs []accessor
+	}
+	accessor struct {
+		Name   string
+		Source string
+		Domain string
+		HTML   bool
+		Params []accessorParam
+		Args   []string // Go expressions passed to T or Thtml after the tag
+	}
+	accessorParam struct {
+		Name string
+		Type string
+	}
+)
+
+// GenerateAccessors returns the source of a Go file in package pkg with one
+// function per message, e.g. WelcomeBack(tag language.Tag, arg1 string)
+// for "Welcome back, %s!". Parameter types follow the format verbs; named
+// placeholders become parameters of type any. Messages whose arguments
+// cannot be typed take them as a variadic ...any. Messages of a domain are
+// translated with TDomain or ThtmlDomain.
+func GenerateAccessors(pkg string, messages []*Message) ([]byte, error) {
+	data := accessorsData{Pkg: sanitizePkgName(pkg)}
+	names := make(map[string]struct{})
+
+	for _, msg := range messages {
+		source := msg.ID
+		if msg.Message != "" {
+			source = msg.Message
+		}
+
+		a := accessor{
+			Name:   uniqueName(names, accessorName(msg.ID)),
+			Source: source,
+			Domain: msg.Domain,
+			HTML:   msg.HTML,
+			Args:   []string{strconv.Quote(msg.ID)},
+		}
+		if msg.Message != "" {
+			a.Args[0] = fmt.Sprintf("i18n.Key(%q, %q)", msg.ID, msg.Message)
+		}
+
+		params, args := accessorParams(source)
+		a.Params = params
+		a.Args = append(a.Args, args...)
+
+		data.HTML = data.HTML || msg.HTML
+		data.Funcs = append(data.Funcs, a)
+	}
+
+	var buf bytes.Buffer
+	if err := accessorsTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// accessorParams derives the parameters of an accessor from the named
+// placeholders or the format verbs of source, and the arguments passing
+// them on.
+func accessorParams(source string) ([]accessorParam, []string) {
+	variadic := []accessorParam{{Name: "a", Type: "...any"}}
+
+	placeholders := Placeholders(source)
+	types, ok := verbTypes(source)
+	switch {
+	case !ok || (len(placeholders) > 0 && len(types) > 0):
+		return variadic, []string{"a..."}
+	case len(placeholders) > 0:
+		var (
+			params []accessorParam
+			args   []string
+			seen   = make(map[string]struct{})
+		)
+		for _, p := range placeholders {
+			name := uniqueName(seen, paramName(p))
+			params = append(params, accessorParam{Name: name, Type: "any"})
+			args = append(args, strconv.Quote(p), name)
+		}
+		return params, args
+	}
+
+	params := make([]accessorParam, len(types))
+	args := make([]string, len(types))
+	for i, typ := range types {
+		params[i] = accessorParam{Name: fmt.Sprintf("arg%d", i+1), Type: typ}
+		args[i] = params[i].Name
+	}
+	return params, args
+}
+
+// verbTypes returns the Go type of each argument of the format verbs in s,
+// honoring explicit argument indexes. It fails on * widths and on arguments
+// that are never used.
+func verbTypes(s string) ([]string, bool) {
+	var (
+		types []string
+		next  int
+	)
+
+	for _, verb := range reVerb.FindAllString(s, -1) {
+		if verb == "%%" {
+			continue
+		}
+		if strings.Contains(verb, "*") {
+			return nil, false
+		}
+
+		if m := reArgIndex.FindString(verb); m != "" {
+			next, _ = strconv.Atoi(m[1 : len(m)-1])
+			next--
+		}
+		if next < 0 {
+			return nil, false
+		}
+
+		for len(types) <= next {
+			types = append(types, "")
+		}
+
+		typ := verbType(verb[len(verb)-1])
+		switch types[next] {
+		case "", typ:
+			types[next] = typ
+		default:
+			types[next] = "any"
+		}
+		next++
+	}
+
+	for _, typ := range types {
+		if typ == "" {
+			return nil, false
+		}
+	}
+	return types, true
+}
+
+func verbType(verb byte) string {
+	switch verb {
+	case 'd', 'b', 'o', 'O', 'c', 'U':
+		return "int"
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		return "float64"
+	case 't':
+		return "bool"
+	case 's', 'q':
+		return "string"
+	default:
+		return "any"
+	}
+}
+
+// accessorName turns a key or message text into an exported Go name, e.g.
+// "checkout.pay_now" into CheckoutPayNow and "Welcome back, %s!" into
+// WelcomeBack. Format verbs, placeholders and HTML tags are left out.
+func accessorName(id string) string {
+	id = reTag.ReplaceAllString(id, " ")
+	id = reVerb.ReplaceAllString(id, " ")
+	id = rePlaceholder.ReplaceAllString(id, " ")
+
+	words := strings.FieldsFunc(id, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxNameWords {
+		words = words[:maxNameWords]
+	}
+
+	var b strings.Builder
+	for _, w := range words {
+		r := []rune(w)
+		b.WriteString(strings.ToUpper(string(r[0])))
+		b.WriteString(string(r[1:]))
+	}
+
+	name := b.String()
+	if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+		name = "Msg" + name
+	}
+	return name
+}
+
+// reservedParams are the names used by the generated code besides the
+// parameters of a message.
+var reservedParams = map[string]struct{}{"tag": {}, "a": {}, "i18n": {}, "language": {}, "template": {}}
+
+// paramName turns a placeholder name into a parameter name that is neither a
+// keyword nor shadows the tag parameter or an imported package.
+func paramName(placeholder string) string {
+	name := strings.ToLower(placeholder[:1]) + placeholder[1:]
+	if _, ok := reservedParams[name]; ok || token.IsKeyword(name) {
+		name += "_"
+	}
+	return name
+}
+
+// uniqueName returns name, or name followed by the first number that makes
+// it unused.
+func uniqueName(used map[string]struct{}, name string) string {
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := used[unique]; !ok {
+			used[unique] = struct{}{}
+			return unique
+		}
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+}
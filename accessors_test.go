@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAccessors(t *testing.T) {
+	messages := []*Message{
+		{ID: "%d items at %.2f"},
+		{ID: "<b>Hi</b> %[2]s %[1]d", HTML: true},
+		{ID: "Welcome back"},
+		{ID: "Welcome back, %s!"},
+		{ID: "checkout.pay", Message: "Pay {amount} for {type}", Placeholders: []string{"amount", "type"}},
+	}
+
+	src, err := GenerateAccessors("msgs", messages)
+	require.NoError(t, err)
+
+	assert.Equal(t, `// Code generated by i18n accessors. DO NOT EDIT.
+
+package msgs
+
+import (
+	"html/template"
+
+	"golang.org/x/text/language"
+
+	"github.com/gowool/i18n"
+)
+
+// ItemsAt translates "%d items at %.2f".
+func ItemsAt(tag language.Tag, arg1 int, arg2 float64) string {
+	return i18n.T(tag, "%d items at %.2f", arg1, arg2)
+}
+
+// Hi translates "<b>Hi</b> %[2]s %[1]d".
+func Hi(tag language.Tag, arg1 int, arg2 string) template.HTML {
+	return i18n.Thtml(tag, "<b>Hi</b> %[2]s %[1]d", arg1, arg2)
+}
+
+// WelcomeBack translates "Welcome back".
+func WelcomeBack(tag language.Tag) string {
+	return i18n.T(tag, "Welcome back")
+}
+
+// WelcomeBack2 translates "Welcome back, %s!".
+func WelcomeBack2(tag language.Tag, arg1 string) string {
+	return i18n.T(tag, "Welcome back, %s!", arg1)
+}
+
+// CheckoutPay translates "Pay {amount} for {type}".
+func CheckoutPay(tag language.Tag, amount any, type_ any) string {
+	return i18n.T(tag, i18n.Key("checkout.pay", "Pay {amount} for {type}"), "amount", amount, "type", type_)
+}
+`, string(src))
+
+	t.Run("Domain", func(t *testing.T) {
+		src, err := GenerateAccessors("msgs", []*Message{
+			{ID: "Save", Domain: "admin"},
+			{ID: "<b>Hi</b> {name}", Domain: "check-out", HTML: true, Placeholders: []string{"name"}},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, string(src), `// Save translates "Save" in the domain admin.
+func Save(tag language.Tag) string {
+	return i18n.TDomain("admin", tag, "Save")
+}`)
+		assert.Contains(t, string(src), `	return i18n.ThtmlDomain("check-out", tag, "<b>Hi</b> {name}", "name", name)`)
+	})
+
+	t.Run("Without HTML", func(t *testing.T) {
+		src, err := GenerateAccessors("msgs", messages[2:3])
+		require.NoError(t, err)
+		assert.NotContains(t, string(src), "html/template")
+	})
+}
+
+func TestAccessorName(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{"checkout.pay_now", "CheckoutPayNow"},
+		{"Welcome back, %s!", "WelcomeBack"},
+		{"Hello {name}", "Hello"},
+		{"one two three four five six", "OneTwoThreeFourFive"},
+		{"über uns", "ÜberUns"},
+		{"404 not found", "Msg404NotFound"},
+		{"%d", "Msg"},
+		{"你好", "Msg你好"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			assert.Equal(t, tt.expected, accessorName(tt.id))
+		})
+	}
+}
+
+func TestAccessorParams(t *testing.T) {
+	tests := []struct {
+		source string
+		params []accessorParam
+		args   []string
+	}{
+		{"Hello", []accessorParam{}, []string{}},
+		{"%s has %d new %t", []accessorParam{{"arg1", "string"}, {"arg2", "int"}, {"arg3", "bool"}}, []string{"arg1", "arg2", "arg3"}},
+		{"%[1]s and %[1]d", []accessorParam{{"arg1", "any"}}, []string{"arg1"}},
+		{"100%% %v", []accessorParam{{"arg1", "any"}}, []string{"arg1"}},
+		{"Hi {name}, {tag}", []accessorParam{{"name", "any"}, {"tag_", "any"}}, []string{`"name"`, "name", `"tag"`, "tag_"}},
+		{"%[2]s", []accessorParam{{"a", "...any"}}, []string{"a..."}},
+		{"%*d", []accessorParam{{"a", "...any"}}, []string{"a..."}},
+		{"{name} %d", []accessorParam{{"a", "...any"}}, []string{"a..."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			params, args := accessorParams(tt.source)
+			assert.Equal(t, tt.params, params)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
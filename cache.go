@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// cacheVersion invalidates caches written by incompatible scanners.
+const cacheVersion = 3
+
+// extractCache stores the messages found in each template, keyed by its path
+// relative to the scanned directory, together with a hash of its content.
+type extractCache struct {
+	Settings string                 `json:"settings"`
+	Files    map[string]*cacheEntry `json:"files"`
+
+	changed bool
+}
+
+type cacheEntry struct {
+	Hash     string     `json:"hash"`
+	Messages []*Message `json:"messages,omitempty"`
+	Warnings []Warning  `json:"warnings,omitempty"`
+}
+
+// loadCache reads the cache at path. A missing or unreadable cache, or one
+// written with other settings, yields an empty cache.
+func loadCache(path, settings string) *extractCache {
+	c := &extractCache{Settings: settings, Files: make(map[string]*cacheEntry)}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	var stored extractCache
+	if err := json.Unmarshal(raw, &stored); err != nil || stored.Settings != settings || stored.Files == nil {
+		c.changed = true
+		return c
+	}
+
+	stored.changed = false
+	return &stored
+}
+
+// get returns a copy of the messages and warnings cached for rel if the hash
+// of its content is unchanged.
+func (c *extractCache) get(rel, hash string) (scanResult, bool) {
+	entry, ok := c.Files[rel]
+	if !ok || entry.Hash != hash {
+		return scanResult{}, false
+	}
+
+	found := make(map[string]*Message, len(entry.Messages))
+	for _, msg := range entry.Messages {
+		cp := *msg
+		cp.Positions = slices.Clone(msg.Positions)
+		cp.Placeholders = slices.Clone(msg.Placeholders)
+		found[cp.ID] = &cp
+	}
+	return scanResult{messages: found, warnings: slices.Clone(entry.Warnings)}, true
+}
+
+// put stores what was found in rel. It must be called before the messages
+// are merged, which modifies them.
+func (c *extractCache) put(rel, hash string, found scanResult) {
+	entry := &cacheEntry{Hash: hash, Warnings: slices.Clone(found.warnings)}
+	for _, id := range slices.Sorted(maps.Keys(found.messages)) {
+		msg := *found.messages[id]
+		msg.Positions = slices.Clone(msg.Positions)
+		msg.Placeholders = slices.Clone(msg.Placeholders)
+		entry.Messages = append(entry.Messages, &msg)
+	}
+
+	c.Files[rel] = entry
+	c.changed = true
+}
+
+// prune drops entries of templates that no longer exist.
+func (c *extractCache) prune(files []templateFile) {
+	keep := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		keep[f.rel] = struct{}{}
+	}
+
+	for rel := range c.Files {
+		if _, ok := keep[rel]; !ok {
+			delete(c.Files, rel)
+			c.changed = true
+		}
+	}
+}
+
+func (c *extractCache) save(path string) error {
+	if !c.changed {
+		return nil
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+
+	c.changed = false
+	return nil
+}
+
+// cacheSettings hashes every setting that changes what is found in a template.
+func (e *Extractor) cacheSettings() string {
+	funcs := make([]string, 0, len(e.funcs))
+	for _, f := range e.funcs {
+		funcs = append(funcs, fmt.Sprintf("%s:%d:%t", f.Name, f.Arg, f.HTML))
+	}
+
+	return contentHash(fmt.Appendf(nil, "v%d\n%s\n%s\n%s", cacheVersion, e.left, e.right, strings.Join(funcs, ",")))
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".i18n-cache")
+
+	c := loadCache(path, "settings")
+	assert.Empty(t, c.Files)
+
+	found := map[string]*Message{
+		"Hello": {ID: "Hello", Positions: []string{"a.html:1:7"}},
+	}
+	warnings := []Warning{{Position: "a.html:1:7", Message: "warning"}}
+	c.put("a.html", "hash-a", scanResult{messages: found, warnings: warnings})
+	c.put("b.html", "hash-b", scanResult{messages: map[string]*Message{}})
+
+	// merging after put must not change the cached entry
+	found["Hello"].Positions = append(found["Hello"].Positions, "b.html:1:1")
+
+	require.NoError(t, c.save(path))
+
+	c = loadCache(path, "settings")
+	assert.False(t, c.changed)
+
+	got, ok := c.get("a.html", "hash-a")
+	require.True(t, ok)
+	assert.Equal(t, map[string]*Message{"Hello": {ID: "Hello", Positions: []string{"a.html:1:7"}}}, got.messages)
+	assert.Equal(t, warnings, got.warnings)
+
+	// the returned messages are copies
+	got.messages["Hello"].Positions[0] = "changed"
+	got, _ = c.get("a.html", "hash-a")
+	assert.Equal(t, "a.html:1:7", got.messages["Hello"].Positions[0])
+
+	_, ok = c.get("a.html", "other-hash")
+	assert.False(t, ok)
+	_, ok = c.get("missing.html", "hash-a")
+	assert.False(t, ok)
+
+	c.prune([]templateFile{{rel: "a.html"}})
+	assert.True(t, c.changed)
+	assert.Len(t, c.Files, 1)
+
+	t.Run("Other settings discard the cache", func(t *testing.T) {
+		c := loadCache(path, "other settings")
+		assert.Empty(t, c.Files)
+		assert.True(t, c.changed)
+	})
+
+	t.Run("Broken cache is discarded", func(t *testing.T) {
+		broken := filepath.Join(t.TempDir(), ".i18n-cache")
+		require.NoError(t, os.WriteFile(broken, []byte("{"), 0644))
+
+		c := loadCache(broken, "settings")
+		assert.Empty(t, c.Files)
+	})
+
+	t.Run("Unchanged cache is not written", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "missing", ".i18n-cache")
+		c := &extractCache{Files: map[string]*cacheEntry{}}
+		assert.NoError(t, c.save(missing))
+
+		c.changed = true
+		assert.Error(t, c.save(missing))
+	})
+}
+
+func TestCacheSettings(t *testing.T) {
+	base := NewExtractor().cacheSettings()
+
+	assert.Equal(t, base, NewExtractor(WithDir("other"), WithExclude("vendor")).cacheSettings())
+	assert.NotEqual(t, base, NewExtractor(WithDelims("[[", "]]")).cacheSettings())
+	assert.NotEqual(t, base, NewExtractor(WithFuncs(Func{Name: "tr", Arg: 1})).cacheSettings())
+	assert.NotEqual(t, base, NewExtractor(WithFuncs(Func{Name: "T", Arg: 1, HTML: true})).cacheSettings())
+}
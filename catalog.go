@@ -0,0 +1,173 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// ErrReadOnlyCatalog is returned by Set when message.DefaultCatalog was
+// replaced by a catalog that cannot be changed.
+var ErrReadOnlyCatalog = errors.New("active catalog is read-only")
+
+// keys are the message keys added with Set and SetMessage, by language.
+var keys keySet
+
+// Lookup returns the message T translates key to in tag, without formatting
+// its arguments: the one of the provider set with SetCatalog, else of the
+// catalog of Printer(tag), by default the active catalog
+// message.DefaultCatalog, else of a library. Like printers, it falls back to
+// the parents of tag, de-DE finds messages of de, and to the supported
+// language tag is printed in.
+func Lookup(tag language.Tag, key string) (string, bool) {
+	return std.Lookup(tag, key)
+}
+
+// appLookup returns the message of key in the catalog of Printer(tag), in
+// the language it prints in, e.g. the supported de for de-AT. The catalog of
+// printers registered with SetPrinter is unknown, so message.DefaultCatalog
+// is consulted for them.
+func appLookup(tag language.Tag, key string) (string, bool) {
+	p := printerOf(tag)
+	if p.cat == nil {
+		return lookup(message.DefaultCatalog, tag, key)
+	}
+	return lookup(p.cat, p.tag, key)
+}
+
+// Set adds or replaces the message of key in tag in the active catalog.
+// Printers use it from the next translation on.
+func Set(tag language.Tag, key, msg string) error {
+	return SetMessage(tag, key, catalog.String(msg))
+}
+
+// SetMessage is like Set for any catalog.Message, such as plural.Selectf.
+func SetMessage(tag language.Tag, key string, msg ...catalog.Message) error {
+	b, ok := message.DefaultCatalog.(*catalog.Builder)
+	if !ok {
+		return ErrReadOnlyCatalog
+	}
+	if err := b.Set(tag, key, msg...); err != nil {
+		return err
+	}
+
+	keys.add(tag, key)
+	return nil
+}
+
+// LoadTranslations adds the translations of every translation file in dir of
+// fsys to the active catalog, see ReadTranslationFS. gotext placeholders are
+// replaced by their format verbs; untranslated messages are left out.
+func LoadTranslations(fsys fs.FS, dir string) error {
+	return loadTranslations(fsys, dir, SetMessage)
+}
+
+// loadTranslations adds the translations of every translation file in dir
+// of fsys with set.
+func loadTranslations(fsys fs.FS, dir string, set func(tag language.Tag, key string, msg ...catalog.Message) error) error {
+	files, err := ReadTranslationFS(fsys, dir)
+	if err != nil {
+		return err
+	}
+	return setTranslations(files, set)
+}
+
+// setTranslations adds the translations of files with set.
+func setTranslations(files []*TranslationFile, set func(tag language.Tag, key string, msg ...catalog.Message) error) error {
+	var errs []error
+	for _, f := range files {
+		tag, err := language.Parse(f.Language)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", f.Language, err))
+			continue
+		}
+
+		for _, m := range f.Messages {
+			msg, err := translationMessage(m)
+			if err == nil && msg != nil {
+				err = set(tag, translationKey(m), msg)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q: %w", tag, m.ID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Keys returns, sorted, the keys Lookup finds a message for in tag: those
+// of the provider set with SetCatalog, of the active catalog and of
+// libraries. Only keys that can be listed are returned: the ones added to
+// message.DefaultCatalog with Set and SetMessage, as LoadTranslations and
+// generated catalogs do, and the ones of providers implementing KeyLister,
+// such as those of this package for the catalogs read by DomainFS, LocaleFS
+// and RegisterLibrary. Messages added otherwise, e.g. with
+// message.SetString or by a catalog of gotext, are not listed.
+func Keys(tag language.Tag) iter.Seq[string] {
+	return std.Keys(tag)
+}
+
+// keyedCatalog is a catalog.Builder listing the keys added with Set.
+type keyedCatalog struct {
+	*catalog.Builder
+	keys keySet
+}
+
+func newKeyedCatalog() *keyedCatalog {
+	return &keyedCatalog{Builder: catalog.NewBuilder()}
+}
+
+func (c *keyedCatalog) Set(tag language.Tag, key string, msg ...catalog.Message) error {
+	if err := c.Builder.Set(tag, key, msg...); err != nil {
+		return err
+	}
+	c.keys.add(tag, key)
+	return nil
+}
+
+// Keys returns the keys that have a message in tag or its parents.
+func (c *keyedCatalog) Keys(tag language.Tag) iter.Seq[string] {
+	return slices.Values(c.keys.list(tag))
+}
+
+// keySet records message keys by language.
+type keySet struct {
+	mu sync.RWMutex
+	m  map[language.Tag]map[string]struct{}
+}
+
+func (s *keySet) add(tag language.Tag, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.m == nil {
+		s.m = make(map[language.Tag]map[string]struct{})
+	}
+	if s.m[tag] == nil {
+		s.m[tag] = make(map[string]struct{})
+	}
+	s.m[tag][key] = struct{}{}
+}
+
+func (s *keySet) list(tag language.Tag) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found := make(map[string]struct{})
+	for {
+		maps.Copy(found, s.m[tag])
+		if tag == language.Und {
+			break
+		}
+		tag = tag.Parent()
+	}
+	return slices.Sorted(maps.Keys(found))
+}
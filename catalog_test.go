@@ -0,0 +1,148 @@
+package i18n
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestSet(t *testing.T) {
+	tag := language.MustParse("rm")
+	regional := language.MustParse("rm-CH")
+
+	_, ok := Lookup(tag, "Hello %s")
+	assert.False(t, ok)
+	assert.Equal(t, "Hello Bob", T(tag, "Hello %s", "Bob"))
+
+	require.NoError(t, Set(tag, "Hello %s", "Servus %s"))
+	require.NoError(t, Set(tag, "Bye", "Pfiat di"))
+	require.NoError(t, Set(regional, "Bye", "Baba"))
+	require.NoError(t, SetMessage(tag, "%d items", plural.Selectf(1, "%d", "=1", "ein Artikel", "other", "%d Artikel")))
+
+	t.Run("Lookup", func(t *testing.T) {
+		msg, ok := Lookup(tag, "Hello %s")
+		assert.True(t, ok)
+		assert.Equal(t, "Servus %s", msg)
+
+		msg, ok = Lookup(regional, "Hello %s")
+		assert.True(t, ok)
+		assert.Equal(t, "Servus %s", msg)
+
+		msg, ok = Lookup(regional, "Bye")
+		assert.True(t, ok)
+		assert.Equal(t, "Baba", msg)
+
+		_, ok = Lookup(tag, "Missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("Printers see new messages", func(t *testing.T) {
+		assert.Equal(t, "Servus Bob", T(tag, "Hello %s", "Bob"))
+		assert.Equal(t, "3 Artikel", T(tag, "%d items", 3))
+
+		require.NoError(t, Set(tag, "Hello %s", "Griaß di %s"))
+		assert.Equal(t, "Griaß di Bob", T(tag, "Hello %s", "Bob"))
+	})
+
+	t.Run("Lookup through providers and libraries", func(t *testing.T) {
+		t.Cleanup(func() {
+			SetCatalog(nil)
+			std.SetLibrary("test-lookup", nil)
+		})
+
+		lazy, err := NewLazyCatalogFS(fstest.MapFS{"locales/rm.yaml": {Data: []byte("test.lookup.lazy: 100% {name}\n")}}, "locales")
+		require.NoError(t, err)
+		SetCatalog(lazy)
+		library := newKeyedCatalog()
+		require.NoError(t, library.Set(tag, "test.lookup.library", catalog.String("Biblioteca")))
+		require.NoError(t, library.Set(tag, "Bye", catalog.String("Library bye")))
+		std.SetLibrary("test-lookup", NewCatalogProvider(library))
+
+		msg, ok := Lookup(regional, "test.lookup.lazy")
+		assert.True(t, ok)
+		assert.Equal(t, "100% {name}", msg)
+
+		msg, ok = Lookup(tag, "test.lookup.library")
+		assert.True(t, ok)
+		assert.Equal(t, "Biblioteca", msg)
+
+		msg, ok = Lookup(tag, "Bye")
+		assert.True(t, ok)
+		assert.Equal(t, "Pfiat di", msg)
+
+		expected := []string{"%d items", "Bye", "Hello %s", "test.lookup.lazy", "test.lookup.library"}
+		assert.Equal(t, expected, slices.Collect(Keys(tag)))
+		assert.Equal(t, expected, slices.Collect(Keys(regional)))
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		assert.Equal(t, []string{"%d items", "Bye", "Hello %s"}, slices.Collect(Keys(tag)))
+		assert.Equal(t, []string{"%d items", "Bye", "Hello %s"}, slices.Collect(Keys(regional)))
+		assert.Empty(t, slices.Collect(Keys(language.MustParse("x-none"))))
+	})
+
+	t.Run("Languages", func(t *testing.T) {
+		assert.Contains(t, Languages(), tag)
+		assert.Contains(t, Languages(), regional)
+	})
+
+	t.Run("Read-only catalog", func(t *testing.T) {
+		defaultCatalog := message.DefaultCatalog
+		cat, err := catalog.NewFromMap(map[string]catalog.Dictionary{})
+		require.NoError(t, err)
+
+		message.DefaultCatalog = cat
+		t.Cleanup(func() { message.DefaultCatalog = defaultCatalog })
+
+		assert.ErrorIs(t, Set(tag, "Bye", "Servus"), ErrReadOnlyCatalog)
+	})
+}
+
+func TestLoadTranslations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/lb.yaml": {Data: []byte("checkout:\n  pay: Elo bezuelen\n\"%d items\":\n  one: een Artikel\n  other: \"%d Artikelen\"\n")},
+		"locales/gsw/messages.gotext.json": {Data: []byte(`{
+    "language": "gsw",
+    "messages": [
+        {
+            "id": "Hello {Arg_1}",
+            "key": "Hello %s",
+            "message": "Hello {Arg_1}",
+            "translation": "Grüezi {Arg_1}",
+            "placeholders": [{"id": "Arg_1", "string": "%[1]s", "argNum": 1}]
+        },
+        {
+            "id": "Untranslated",
+            "message": "Untranslated",
+            "translation": ""
+        }
+    ]
+}`)},
+		"locales/README.md": {Data: []byte("not a translation file")},
+	}
+	require.NoError(t, LoadTranslations(fsys, "locales"))
+
+	lb, gsw := language.MustParse("lb"), language.MustParse("gsw")
+	assert.Equal(t, "Elo bezuelen", T(lb, "checkout.pay", "Pay now"))
+	assert.Equal(t, "3 Artikelen", T(lb, "%d items", 3))
+	assert.Equal(t, "een Artikel", T(lb, "%d items", 1))
+	assert.Equal(t, "Grüezi Bob", T(gsw, "Hello %s", "Bob"))
+
+	_, ok := Lookup(gsw, "Untranslated")
+	assert.False(t, ok)
+
+	t.Run("Invalid files", func(t *testing.T) {
+		err := LoadTranslations(fstest.MapFS{"de.yaml": {Data: []byte("- a")}}, ".")
+		assert.ErrorContains(t, err, "de.yaml")
+
+		err = LoadTranslations(fstest.MapFS{"messages.yaml": {Data: []byte("a: b")}}, ".")
+		assert.Error(t, err)
+	})
+}
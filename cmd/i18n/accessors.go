@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/gowool/i18n"
+)
+
+func accessors() *cli.Command {
+	return &cli.Command{
+		Name:      "accessors",
+		Usage:     "Generate typed Go functions for extracted messages",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "messages",
+				Value: "messages.json",
+				Usage: "messages JSON written by extract --out",
+			},
+			&cli.StringFlag{
+				Name:  "out",
+				Value: "messages.go",
+				Usage: "generated Go file",
+			},
+			&cli.StringFlag{
+				Name:  "pkg",
+				Value: "msgs",
+				Usage: "package name to use in the generated Go file",
+			},
+		},
+		Action: func(_ context.Context, command *cli.Command) error {
+			return generateAccessors(command.String("messages"), command.String("out"), command.String("pkg"))
+		},
+	}
+}
+
+func generateAccessors(in, out, pkg string) error {
+	messages, err := i18n.ReadMessagesFile(in)
+	if err != nil {
+		return err
+	}
+
+	src, err := i18n.GenerateAccessors(pkg, messages)
+	if err != nil {
+		return fmt.Errorf("generate accessors: %w", err)
+	}
+
+	return os.WriteFile(out, src, 0644)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccessorsCommandStructure tests the structure of the accessors command
+func TestAccessorsCommandStructure(t *testing.T) {
+	cmd := accessors()
+
+	assert.Equal(t, "accessors", cmd.Name)
+	assert.Equal(t, "Generate typed Go functions for extracted messages", cmd.Usage)
+	require.Len(t, cmd.Flags, 3)
+
+	flagNames := make([]string, len(cmd.Flags))
+	for i, f := range cmd.Flags {
+		flagNames[i] = f.Names()[0]
+	}
+	assert.Equal(t, []string{"messages", "out", "pkg"}, flagNames)
+}
+
+// TestAccessorsCommand tests generating accessors from extracted messages
+func TestAccessorsCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	createTempFile(t, tempDir, "messages.json", `{"messages": [{"id": "Welcome back, %s!"}]}`)
+	out := filepath.Join(tempDir, "messages.go")
+
+	cmd := buildCLI(nil)
+	err := cmd.Run(context.Background(), []string{"i18n", "accessors", "--messages", filepath.Join(tempDir, "messages.json"), "--out", out})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "package msgs")
+	assert.Contains(t, string(content), "func WelcomeBack(tag language.Tag, arg1 string) string {")
+
+	err = cmd.Run(context.Background(), []string{"i18n", "accessors", "--messages", filepath.Join(tempDir, "missing.json"), "--out", out})
+	assert.Error(t, err)
+
+	createTempFile(t, tempDir, "messages.admin.json", `{"domain": "admin", "messages": [{"id": "Save"}]}`)
+	err = cmd.Run(context.Background(), []string{"i18n", "accessors", "--messages", filepath.Join(tempDir, "messages.admin.json"), "--out", out})
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `return i18n.TDomain("admin", tag, "Save")`)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/gowool/i18n"
+)
+
+func export() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Export translation files for translators",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "dir",
+				Value: "locales",
+				Usage: "directory to scan for translation files",
+			},
+			&cli.StringFlag{
+				Name:  "out",
+				Value: "translations",
+				Usage: "directory to write one file per language to, or the CSV file",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "yaml",
+				Usage: "format of the exported files: yaml, toml or csv",
+			},
+		},
+		Action: func(_ context.Context, command *cli.Command) error {
+			return exportTranslations(command.String("dir"), command.String("out"), command.String("format"), writer(command))
+		},
+	}
+}
+
+func exportTranslations(dir, out, format string, w io.Writer) error {
+	if _, ok := i18n.CodecFor("." + format); (!ok || format == "gotext.json") && format != "csv" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	files, err := i18n.ReadTranslationDir(dir)
+	if err != nil {
+		return err
+	}
+
+	if format == "csv" {
+		return exportCSV(files, out, w)
+	}
+
+	exported, err := i18n.ExportTranslations(files...)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+
+	for _, f := range exported {
+		path := filepath.Join(out, f.Language+"."+format)
+		if err := i18n.WriteTranslationFile(path, f); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "Wrote %d messages → %s\n", len(f.Messages), path)
+	}
+	return nil
+}
+
+// exportCSV writes files as a single CSV file: out if it has the .csv
+// extension, otherwise translations.csv in the directory out.
+func exportCSV(files []*i18n.TranslationFile, out string, w io.Writer) error {
+	path := out
+	if filepath.Ext(out) != ".csv" {
+		if err := os.MkdirAll(out, 0755); err != nil {
+			return err
+		}
+		path = filepath.Join(out, "translations.csv")
+	}
+
+	var buf bytes.Buffer
+	if err := i18n.WriteCSV(&buf, files...); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "Wrote translations → %s\n", path)
+	return nil
+}
+
+// writer returns the writer of the root command, os.Stdout if not set.
+func writer(command *cli.Command) io.Writer {
+	if w := command.Root().Writer; w != nil {
+		return w
+	}
+	return os.Stdout
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deGotextFile = `{
+	"language": "de-DE",
+	"messages": [
+		{
+			"id": "Hello {Arg_1}",
+			"key": "Hello %s",
+			"message": "Hello {Arg_1}",
+			"translation": "Hallo {Arg_1}",
+			"placeholders": [{"id": "Arg_1", "string": "%[1]s", "argNum": 1}]
+		},
+		{
+			"id": "checkout.pay",
+			"message": "Pay {amount}",
+			"translation": "",
+			"comment": "checkout button"
+		}
+	]
+}`
+
+// TestExportCommandStructure tests the structure of the export command
+func TestExportCommandStructure(t *testing.T) {
+	cmd := export()
+
+	assert.Equal(t, "export", cmd.Name)
+	assert.Equal(t, "Export translation files for translators", cmd.Usage)
+	require.Len(t, cmd.Flags, 3)
+
+	flagNames := make([]string, len(cmd.Flags))
+	for i, f := range cmd.Flags {
+		flagNames[i] = f.Names()[0]
+	}
+	assert.Equal(t, []string{"dir", "out", "format"}, flagNames)
+}
+
+// TestExportCommand tests exporting gotext translation files to YAML, TOML and CSV
+func TestExportCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	locales := filepath.Join(tempDir, "locales")
+	require.NoError(t, os.MkdirAll(filepath.Join(locales, "de-DE"), 0755))
+	createTempFile(t, filepath.Join(locales, "de-DE"), "messages.gotext.json", deGotextFile)
+	out := filepath.Join(tempDir, "translations")
+
+	var buf bytes.Buffer
+	cmd := buildCLI(nil)
+	cmd.Writer = &buf
+
+	err := cmd.Run(context.Background(), []string{"i18n", "export", "--dir", locales, "--out", out})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Wrote 2 messages → "+filepath.Join(out, "de-DE.yaml"))
+
+	content, err := os.ReadFile(filepath.Join(out, "de-DE.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello %s: Hallo %[1]s\n# checkout button\ncheckout:\n  pay: \"\"\n", string(content))
+
+	err = cmd.Run(context.Background(), []string{"i18n", "export", "--dir", locales, "--out", out, "--format", "toml"})
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(filepath.Join(out, "de-DE.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, "\"Hello %s\" = \"Hallo %[1]s\"\n# checkout button\ncheckout.pay = \"\"\n", string(content))
+
+	err = cmd.Run(context.Background(), []string{"i18n", "export", "--dir", locales, "--out", out, "--format", "csv"})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Wrote translations → "+filepath.Join(out, "translations.csv"))
+
+	content, err = os.ReadFile(filepath.Join(out, "translations.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "id,comment,source,translatorComment,positions,de-DE\nHello %s,,Hello %[1]s,,,Hallo %[1]s\ncheckout.pay,checkout button,Pay {amount},,,\n", string(content))
+
+	err = cmd.Run(context.Background(), []string{"i18n", "export", "--dir", locales, "--out", filepath.Join(tempDir, "review.csv"), "--format", "csv"})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(tempDir, "review.csv"))
+
+	err = cmd.Run(context.Background(), []string{"i18n", "export", "--dir", locales, "--out", out, "--format", "xml"})
+	assert.ErrorContains(t, err, `unknown format "xml"`)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"time"
 
 	"github.com/urfave/cli/v3"
 )
@@ -36,6 +37,82 @@ func extract(extractor func(*cli.Command) error) *cli.Command {
 				Usage: "template extensions to consider",
 				Value: []string{".html", ".htm", ".tmpl", ".gohtml", ".txt", ".tpl"},
 			},
+			&cli.StringFlag{
+				Name:  "left-delim",
+				Value: "{{",
+				Usage: "left template action delimiter",
+			},
+			&cli.StringFlag{
+				Name:  "right-delim",
+				Value: "}}",
+				Usage: "right template action delimiter",
+			},
+			&cli.StringSliceFlag{
+				Name:  "func",
+				Usage: "translation function as name[:key position][:html]",
+				Value: []string{"i18n", "T", "t", "Thtml:1:html"},
+			},
+			&cli.StringSliceFlag{
+				Name:  "include",
+				Usage: "only scan templates matching these doublestar globs, relative to --dir",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "skip files and directories matching these doublestar globs, relative to --dir",
+			},
+			&cli.BoolFlag{
+				Name:  "no-gitignore",
+				Usage: "also scan files ignored by .gitignore",
+			},
+			&cli.BoolFlag{
+				Name:  "hidden",
+				Usage: "also scan hidden files and directories",
+			},
+			&cli.StringFlag{
+				Name:  "config",
+				Usage: "extraction config file (default: i18n.yaml in the current directory or its parents)",
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "number of templates scanned concurrently (default: GOMAXPROCS)",
+			},
+			&cli.StringFlag{
+				Name:  "cache",
+				Usage: "incremental extraction cache file, e.g. .i18n-cache",
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "keep running and extract again whenever templates change",
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Value: time.Second,
+				Usage: "how often --watch polls for template changes",
+			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: "fail without writing any file when templates produce warnings",
+			},
+			&cli.BoolFlag{
+				Name:  "file-positions",
+				Usage: "list message positions as file names without line and column",
+			},
+			&cli.StringSliceFlag{
+				Name:  "domain",
+				Usage: "map templates to a domain as name=glob, the glob relative to --dir",
+			},
+			&cli.StringSliceFlag{
+				Name:  "module",
+				Usage: "also scan the templates of this Go module required by the main module",
+			},
+			&cli.StringFlag{
+				Name:  "source-language",
+				Usage: "language the messages are written in (default: en)",
+			},
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: "fail with a diff instead of writing when --out or --gofile is out of date",
+			},
 		},
 		Action: func(_ context.Context, command *cli.Command) error {
 			return extractor(command)
@@ -55,7 +55,7 @@ func TestExtract(t *testing.T) {
 				assert.Equal(t, " ", cmd.ArgsUsage)
 
 				// Verify flags count
-				require.Len(t, cmd.Flags, 5)
+				require.Len(t, cmd.Flags, 23)
 
 				// Verify flag types and default values by examining flag definitions
 				flagMap := make(map[string]cli.Flag)
@@ -104,6 +104,21 @@ func TestExtract(t *testing.T) {
 				expectedExts := []string{".html", ".htm", ".tmpl", ".gohtml", ".txt", ".tpl"}
 				assert.Equal(t, expectedExts, extStringSliceFlag.Value)
 
+				// Check delimiter flags
+				leftFlag, exists := flagMap["left-delim"]
+				require.True(t, exists, "left-delim flag should exist")
+				assert.Equal(t, "{{", leftFlag.(*cli.StringFlag).Value)
+
+				rightFlag, exists := flagMap["right-delim"]
+				require.True(t, exists, "right-delim flag should exist")
+				assert.Equal(t, "}}", rightFlag.(*cli.StringFlag).Value)
+
+				// Check func flag
+				funcFlag, exists := flagMap["func"]
+				require.True(t, exists, "func flag should exist")
+				assert.IsType(t, &cli.StringSliceFlag{}, funcFlag)
+				assert.Equal(t, []string{"i18n", "T", "t", "Thtml:1:html"}, funcFlag.(*cli.StringSliceFlag).Value)
+
 				// Test action function
 				require.NotNil(t, cmd.Action)
 
@@ -155,7 +170,7 @@ func TestExtractCommandStructure(t *testing.T) {
 
 	// Test that command has flags
 	assert.NotEmpty(t, cmd.Flags)
-	assert.Len(t, cmd.Flags, 5) // dir, out, gofile, pkg, ext
+	assert.Len(t, cmd.Flags, 23) // dir, out, gofile, pkg, ext, left-delim, right-delim, func, include, exclude, no-gitignore, hidden, config, workers, cache, watch, interval, strict, file-positions, domain, module, source-language, check
 
 	// Test that command has an action
 	assert.NotNil(t, cmd.Action)
@@ -170,16 +185,22 @@ func TestExtractCommandStructure(t *testing.T) {
 			flagTypes[i] = "StringFlag"
 		case *cli.StringSliceFlag:
 			flagTypes[i] = "StringSliceFlag"
+		case *cli.BoolFlag:
+			flagTypes[i] = "BoolFlag"
+		case *cli.IntFlag:
+			flagTypes[i] = "IntFlag"
+		case *cli.DurationFlag:
+			flagTypes[i] = "DurationFlag"
 		default:
 			flagTypes[i] = "Unknown"
 		}
 	}
 
-	expectedFlagNames := []string{"dir", "out", "gofile", "pkg", "ext"}
+	expectedFlagNames := []string{"dir", "out", "gofile", "pkg", "ext", "left-delim", "right-delim", "func", "include", "exclude", "no-gitignore", "hidden", "config", "workers", "cache", "watch", "interval", "strict", "file-positions", "domain", "module", "source-language", "check"}
 	assert.Equal(t, expectedFlagNames, flagNames)
 
 	// Verify specific flag types
-	expectedTypes := []string{"StringFlag", "StringFlag", "StringFlag", "StringFlag", "StringSliceFlag"}
+	expectedTypes := []string{"StringFlag", "StringFlag", "StringFlag", "StringFlag", "StringSliceFlag", "StringFlag", "StringFlag", "StringSliceFlag", "StringSliceFlag", "StringSliceFlag", "BoolFlag", "BoolFlag", "StringFlag", "IntFlag", "StringFlag", "BoolFlag", "DurationFlag", "BoolFlag", "BoolFlag", "StringSliceFlag", "StringSliceFlag", "StringFlag", "BoolFlag"}
 	assert.Equal(t, expectedTypes, flagTypes)
 }
 
@@ -202,7 +223,7 @@ func TestExtractFlagValuesStructure(t *testing.T) {
 	cmd := extract(extractorFunc)
 
 	// Verify flag structure
-	require.Len(t, cmd.Flags, 5)
+	require.Len(t, cmd.Flags, 23)
 
 	// Find each flag by name
 	var dirFlag, outFlag, gofileFlag, pkgFlag, extFlag cli.Flag
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/gowool/i18n"
+)
+
+func generate() *cli.Command {
+	return &cli.Command{
+		Name:      "generate",
+		Usage:     "Generate a Go catalog from translation files",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "dir",
+				Value: "locales",
+				Usage: "directory to scan for translation files (*.gotext.json, *.yaml, *.toml)",
+			},
+			&cli.StringFlag{
+				Name:  "out",
+				Value: "catalog.go",
+				Usage: "generated Go file",
+			},
+			&cli.StringFlag{
+				Name:  "pkg",
+				Value: "main",
+				Usage: "package name to use in the generated Go file",
+			},
+		},
+		Action: func(_ context.Context, command *cli.Command) error {
+			return generateCatalog(command.String("dir"), command.String("out"), command.String("pkg"))
+		},
+	}
+}
+
+func generateCatalog(dir, out, pkg string) error {
+	files, err := i18n.ReadTranslationDir(dir)
+	if err != nil {
+		return err
+	}
+
+	src, err := i18n.GenerateCatalog(pkg, files...)
+	if err != nil {
+		return fmt.Errorf("generate catalog: %w", err)
+	}
+
+	return os.WriteFile(out, src, 0644)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerateCommandStructure tests the structure of the generate command
+func TestGenerateCommandStructure(t *testing.T) {
+	cmd := generate()
+
+	assert.Equal(t, "generate", cmd.Name)
+	assert.Equal(t, "Generate a Go catalog from translation files", cmd.Usage)
+	require.Len(t, cmd.Flags, 3)
+
+	flagNames := make([]string, len(cmd.Flags))
+	for i, f := range cmd.Flags {
+		flagNames[i] = f.Names()[0]
+	}
+	assert.Equal(t, []string{"dir", "out", "pkg"}, flagNames)
+}
+
+// TestGenerateCommand tests generating a catalog from translation files
+func TestGenerateCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "de-DE"), 0755))
+	createTempFile(t, filepath.Join(tempDir, "de-DE"), "messages.gotext.json", `{
+		"language": "de-DE",
+		"messages": [{"id": "Hello", "message": "Hello", "translation": "Hallo"}]
+	}`)
+	out := filepath.Join(tempDir, "catalog.go")
+
+	cmd := buildCLI(nil)
+	err := cmd.Run(context.Background(), []string{"i18n", "generate", "--dir", tempDir, "--out", out, "--pkg", "locales"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "package locales")
+	assert.Contains(t, string(content), `set("de-DE", "Hello", catalog.String("Hallo"))`)
+
+	err = cmd.Run(context.Background(), []string{"i18n", "generate", "--dir", filepath.Join(tempDir, "missing"), "--out", out})
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"golang.org/x/text/language"
+
+	"github.com/gowool/i18n"
+)
+
+func importer() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import edited translations into gotext translation files",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "in",
+				Value: "translations",
+				Usage: "translation file, CSV file or directory of translation files to import",
+			},
+			&cli.StringFlag{
+				Name:  "dir",
+				Value: "locales",
+				Usage: "directory of the *.gotext.json translation files to update",
+			},
+		},
+		Action: func(_ context.Context, command *cli.Command) error {
+			return importTranslations(command.String("in"), command.String("dir"), writer(command))
+		},
+	}
+}
+
+// gotextFile is a gotext translation file and its path.
+type gotextFile struct {
+	path    string
+	file    *i18n.TranslationFile
+	changed int
+}
+
+func importTranslations(in, dir string, w io.Writer) error {
+	imported, err := readImport(in)
+	if err != nil {
+		return err
+	}
+
+	targets, err := readGotextFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, src := range imported {
+		lang := normalizeLanguage(src.Language)
+
+		found := 0
+		unknown := make(map[string]int)
+		conflicts := make(map[string]struct{})
+		for _, t := range targets {
+			if normalizeLanguage(t.file.Language) != lang {
+				continue
+			}
+			found++
+
+			res, err := t.file.Import(src)
+			if err != nil {
+				return err
+			}
+			t.changed += res.Changed
+			for _, key := range res.Unknown {
+				unknown[key]++
+			}
+			for _, conflict := range res.Conflicts {
+				conflicts[conflict] = struct{}{}
+			}
+		}
+
+		if found == 0 {
+			_, _ = fmt.Fprintf(w, "warning: no translation files for %q in %s\n", src.Language, dir)
+			continue
+		}
+		for _, key := range slices.Sorted(maps.Keys(unknown)) {
+			if unknown[key] == found {
+				_, _ = fmt.Fprintf(w, "warning: %s: unknown message %q\n", src.Language, key)
+			}
+		}
+		for _, conflict := range slices.Sorted(maps.Keys(conflicts)) {
+			_, _ = fmt.Fprintf(w, "conflict: %s: %s\n", src.Language, conflict)
+		}
+	}
+
+	var errs []error
+	for _, t := range targets {
+		if t.changed > 0 {
+			errs = append(errs, t.file.Validate())
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for _, t := range targets {
+		if t.changed == 0 {
+			continue
+		}
+		if err := i18n.WriteTranslationFile(t.path, t.file); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "Updated %d translations → %s\n", t.changed, t.path)
+	}
+	return nil
+}
+
+// readImport reads the translation file in, the language columns of the
+// CSV file in, or every translation file in the directory in.
+func readImport(in string) ([]*i18n.TranslationFile, error) {
+	info, err := os.Stat(in)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return i18n.ReadTranslationDir(in)
+	}
+
+	if strings.HasSuffix(in, ".csv") {
+		r, err := os.Open(in)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = r.Close() }()
+
+		files, err := i18n.ReadCSV(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", in, err)
+		}
+		return files, nil
+	}
+
+	f, err := i18n.ReadTranslationFile(in)
+	if err != nil {
+		return nil, err
+	}
+	return []*i18n.TranslationFile{f}, nil
+}
+
+// readGotextFiles reads every *.gotext.json file in dir and its subdirectories.
+func readGotextFiles(dir string) ([]*gotextFile, error) {
+	var files []*gotextFile
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".gotext.json") {
+			return nil
+		}
+
+		f, err := i18n.ReadTranslationFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &gotextFile{path: path, file: f})
+
+		return nil
+	})
+
+	return files, err
+}
+
+// normalizeLanguage returns lang in its canonical form, e.g. de-DE for de_DE.
+func normalizeLanguage(lang string) string {
+	if tag, err := language.Parse(lang); err == nil {
+		return tag.String()
+	}
+	return lang
+}
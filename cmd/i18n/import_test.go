@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gowool/i18n"
+)
+
+// TestImportCommandStructure tests the structure of the import command
+func TestImportCommandStructure(t *testing.T) {
+	cmd := importer()
+
+	assert.Equal(t, "import", cmd.Name)
+	assert.Equal(t, "Import edited translations into gotext translation files", cmd.Usage)
+	require.Len(t, cmd.Flags, 2)
+
+	flagNames := make([]string, len(cmd.Flags))
+	for i, f := range cmd.Flags {
+		flagNames[i] = f.Names()[0]
+	}
+	assert.Equal(t, []string{"in", "dir"}, flagNames)
+}
+
+// TestImportCommand tests importing edited YAML translations into gotext files
+func TestImportCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	locales := filepath.Join(tempDir, "locales")
+	require.NoError(t, os.MkdirAll(filepath.Join(locales, "de-DE"), 0755))
+	createTempFile(t, filepath.Join(locales, "de-DE"), "messages.gotext.json", deGotextFile)
+	translations := filepath.Join(tempDir, "translations")
+	require.NoError(t, os.MkdirAll(translations, 0755))
+	createTempFile(t, translations, "de_DE.yaml", "Hello %s: Servus %[1]s\ncheckout:\n  pay: Zahle {amount}\nBye: Tschüss\n")
+	createTempFile(t, translations, "fr.toml", `"Hello %s" = "Salut %[1]s"`)
+
+	var buf bytes.Buffer
+	cmd := buildCLI(nil)
+	cmd.Writer = &buf
+
+	err := cmd.Run(context.Background(), []string{"i18n", "import", "--in", translations, "--dir", locales})
+	require.NoError(t, err)
+
+	path := filepath.Join(locales, "de-DE", "messages.gotext.json")
+	assert.Contains(t, buf.String(), `warning: de-DE: unknown message "Bye"`)
+	assert.Contains(t, buf.String(), `warning: no translation files for "fr"`)
+	assert.Contains(t, buf.String(), "Updated 2 translations → "+path)
+
+	f, err := i18n.ReadTranslationFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Servus {Arg_1}", f.Messages[0].Translation.Msg)
+	assert.Equal(t, "Zahle {amount}", f.Messages[1].Translation.Msg)
+	assert.Equal(t, "checkout button", f.Messages[1].Comment)
+
+	t.Run("Unchanged files are not rewritten", func(t *testing.T) {
+		buf.Reset()
+		err := cmd.Run(context.Background(), []string{"i18n", "import", "--in", filepath.Join(translations, "de_DE.yaml"), "--dir", locales})
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "Updated")
+	})
+
+	t.Run("Invalid placeholders", func(t *testing.T) {
+		createTempFile(t, translations, "de_DE.yaml", "checkout:\n  pay: Zahle {betrag}\n")
+
+		err := cmd.Run(context.Background(), []string{"i18n", "import", "--in", filepath.Join(translations, "de_DE.yaml"), "--dir", locales})
+		assert.ErrorContains(t, err, "unknown placeholders {betrag}")
+
+		f, err := i18n.ReadTranslationFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "Zahle {amount}", f.Messages[1].Translation.Msg)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		createTempFile(t, tempDir, "review.csv", "id,source,de-DE\n"+
+			"Hello %s,Hello %[1]s,Grüß dich %[1]s\n"+
+			"checkout.pay,Pay now,Jetzt zahlen\n")
+
+		buf.Reset()
+		err := cmd.Run(context.Background(), []string{"i18n", "import", "--in", filepath.Join(tempDir, "review.csv"), "--dir", locales})
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), `conflict: de-DE: "checkout.pay": source changed to "Pay {amount}"`)
+		assert.Contains(t, buf.String(), "Updated 1 translations → "+path)
+
+		f, err := i18n.ReadTranslationFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "Grüß dich {Arg_1}", f.Messages[0].Translation.Msg)
+		assert.Equal(t, "Zahle {amount}", f.Messages[1].Translation.Msg)
+	})
+
+	t.Run("Missing input", func(t *testing.T) {
+		err := cmd.Run(context.Background(), []string{"i18n", "import", "--in", filepath.Join(tempDir, "missing"), "--dir", locales})
+		assert.Error(t, err)
+	})
+}
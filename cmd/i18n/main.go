@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/urfave/cli/v3"
+	"golang.org/x/text/language"
 
 	"github.com/gowool/i18n"
 )
@@ -13,15 +16,7 @@ import (
 var version = "v0.0.1"
 
 func main() {
-	a := buildCLI(func(command *cli.Command) error {
-		return i18n.NewExtractor(
-			command.String("dir"),
-			command.String("out"),
-			command.String("pkg"),
-			command.String("gofile"),
-			command.StringSlice("ext")...,
-		).Extract()
-	})
+	a := buildCLI(runExtract)
 
 	if err := a.Run(context.Background(), os.Args); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -29,11 +24,120 @@ func main() {
 	}
 }
 
+func runExtract(command *cli.Command) error {
+	opts, err := extractorOptions(command)
+	if err != nil {
+		return err
+	}
+
+	if w := command.Root().Writer; w != nil {
+		opts = append(opts, i18n.WithLog(w))
+	}
+
+	extractor := i18n.NewExtractor(opts...)
+
+	if command.Bool("check") {
+		return extractor.Check()
+	}
+
+	if command.Bool("watch") {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		return extractor.Watch(ctx, command.Duration("interval"))
+	}
+
+	return extractor.Extract()
+}
+
+// extractorOptions loads the config file, --config or i18n.yaml found in the
+// current directory or its parents, and overrides it with explicitly set flags.
+func extractorOptions(command *cli.Command) ([]i18n.ExtractorOption, error) {
+	var opts []i18n.ExtractorOption
+
+	path := command.String("config")
+	if path == "" {
+		path, _ = i18n.FindConfig(".")
+	}
+	if path != "" {
+		cfg, err := i18n.LoadExtractorConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, i18n.WithConfig(cfg))
+	}
+
+	var flags i18n.ExtractorConfig
+	for name, value := range map[string]*string{
+		"dir":         &flags.Dir,
+		"out":         &flags.Out,
+		"cache":       &flags.Cache,
+		"pkg":         &flags.Pkg,
+		"gofile":      &flags.GoFile,
+		"left-delim":  &flags.LeftDelim,
+		"right-delim": &flags.RightDelim,
+	} {
+		if command.IsSet(name) {
+			*value = command.String(name)
+		}
+	}
+	if command.IsSet("ext") {
+		flags.Exts = command.StringSlice("ext")
+	}
+	if command.IsSet("include") {
+		flags.Include = command.StringSlice("include")
+	}
+	if command.IsSet("exclude") {
+		flags.Exclude = command.StringSlice("exclude")
+	}
+	flags.NoGitignore = command.Bool("no-gitignore")
+	flags.Hidden = command.Bool("hidden")
+	flags.Workers = command.Int("workers")
+	flags.Strict = command.Bool("strict")
+	flags.FilePositions = command.Bool("file-positions")
+	if command.IsSet("source-language") {
+		tag, err := language.Parse(command.String("source-language"))
+		if err != nil {
+			return nil, fmt.Errorf("source language: %w", err)
+		}
+		flags.SourceLanguage = tag
+	}
+	if command.IsSet("module") {
+		flags.Modules = command.StringSlice("module")
+	}
+	if command.IsSet("domain") {
+		flags.Domains = make(map[string][]string)
+		for _, spec := range command.StringSlice("domain") {
+			name, pattern, ok := strings.Cut(spec, "=")
+			if !ok || name == "" || pattern == "" {
+				return nil, fmt.Errorf("invalid domain %q: expected name=glob", spec)
+			}
+			flags.Domains[name] = append(flags.Domains[name], pattern)
+		}
+	}
+	if command.IsSet("func") {
+		funcs, err := i18n.ParseFuncs(command.StringSlice("func"))
+		if err != nil {
+			return nil, err
+		}
+		flags.Funcs = funcs
+	}
+
+	opts = append(opts, i18n.WithConfig(flags))
+
+	// an explicitly empty --gofile skips the gotext stub
+	if command.IsSet("gofile") && command.String("gofile") == "" {
+		opts = append(opts, i18n.WithGoFile(""))
+	}
+
+	return opts, nil
+}
+
 func buildCLI(extractor func(*cli.Command) error) *cli.Command {
 	return &cli.Command{
 		Name:     "i18n",
 		Usage:    "i18n tool",
 		Version:  version,
-		Commands: []*cli.Command{extract(extractor)},
+		Commands: []*cli.Command{extract(extractor), validate(), generate(), accessors(), export(), importer(), update()},
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -63,7 +64,7 @@ func TestBuildCLI(t *testing.T) {
 
 			// Verify command structure
 			assert.NotNil(t, cmd.Commands)
-			assert.Len(t, cmd.Commands, 1)
+			assert.Len(t, cmd.Commands, 7)
 
 			// Verify subcommand is extract
 			extractCmd := cmd.Commands[0]
@@ -72,14 +73,14 @@ func TestBuildCLI(t *testing.T) {
 
 			// Verify extract command has flags
 			assert.NotEmpty(t, extractCmd.Flags)
-			assert.Len(t, extractCmd.Flags, 5) // dir, out, gofile, pkg, ext
+			assert.Len(t, extractCmd.Flags, 23) // dir, out, gofile, pkg, ext, left-delim, right-delim, func, include, exclude, no-gitignore, hidden, config, workers, cache, watch, interval, strict, file-positions, domain, module, source-language, check
 
 			// Verify flag names
 			flagNames := make([]string, len(extractCmd.Flags))
 			for i, f := range extractCmd.Flags {
 				flagNames[i] = f.Names()[0]
 			}
-			expectedFlagNames := []string{"dir", "out", "gofile", "pkg", "ext"}
+			expectedFlagNames := []string{"dir", "out", "gofile", "pkg", "ext", "left-delim", "right-delim", "func", "include", "exclude", "no-gitignore", "hidden", "config", "workers", "cache", "watch", "interval", "strict", "file-positions", "domain", "module", "source-language", "check"}
 			assert.Equal(t, expectedFlagNames, flagNames)
 		})
 	}
@@ -100,13 +101,37 @@ func TestBuildCLICommandStructure(t *testing.T) {
 	assert.Nil(t, cmd.Action)
 
 	// Test subcommands
-	require.Len(t, cmd.Commands, 1)
+	require.Len(t, cmd.Commands, 7)
 
 	extractCmd := cmd.Commands[0]
 	assert.Equal(t, "extract", extractCmd.Name)
 	assert.Equal(t, "Extract i18n messages from templates", extractCmd.Usage)
 	assert.Equal(t, " ", extractCmd.ArgsUsage)
 	assert.NotNil(t, extractCmd.Action)
+
+	validateCmd := cmd.Commands[1]
+	assert.Equal(t, "validate", validateCmd.Name)
+	assert.NotNil(t, validateCmd.Action)
+
+	generateCmd := cmd.Commands[2]
+	assert.Equal(t, "generate", generateCmd.Name)
+	assert.NotNil(t, generateCmd.Action)
+
+	accessorsCmd := cmd.Commands[3]
+	assert.Equal(t, "accessors", accessorsCmd.Name)
+	assert.NotNil(t, accessorsCmd.Action)
+
+	exportCmd := cmd.Commands[4]
+	assert.Equal(t, "export", exportCmd.Name)
+	assert.NotNil(t, exportCmd.Action)
+
+	importCmd := cmd.Commands[5]
+	assert.Equal(t, "import", importCmd.Name)
+	assert.NotNil(t, importCmd.Action)
+
+	updateCmd := cmd.Commands[6]
+	assert.Equal(t, "update", updateCmd.Name)
+	assert.NotNil(t, updateCmd.Action)
 }
 
 // TestBuildCLIIntegration tests the integration between buildCLI and extract functions
@@ -118,7 +143,7 @@ func TestBuildCLIIntegration(t *testing.T) {
 	}
 
 	cliCmd := buildCLI(extractorFunc)
-	require.Len(t, cliCmd.Commands, 1)
+	require.Len(t, cliCmd.Commands, 7)
 
 	extractCmd := cliCmd.Commands[0]
 	require.NotNil(t, extractCmd.Action)
@@ -176,15 +201,7 @@ func TestMainFunctionIntegration(t *testing.T) {
 
 	// Note: We can't easily test main() directly due to os.Exit()
 	// But we can test the buildCLI part that main() uses
-	cliCmd := buildCLI(func(command *cli.Command) error {
-		return i18n.NewExtractor(
-			command.String("dir"),
-			command.String("out"),
-			command.String("pkg"),
-			command.String("gofile"),
-			command.StringSlice("ext")...,
-		).Extract()
-	})
+	cliCmd := buildCLI(runExtract)
 
 	// Close pipe and restore outputs
 	_ = w.Close()
@@ -241,15 +258,7 @@ func TestMainFunctionErrorHandling(t *testing.T) {
 	}
 
 	// Create CLI command (same as main would do)
-	cliCmd := buildCLI(func(command *cli.Command) error {
-		return i18n.NewExtractor(
-			command.String("dir"),
-			command.String("out"),
-			command.String("pkg"),
-			command.String("gofile"),
-			command.StringSlice("ext")...,
-		).Extract()
-	})
+	cliCmd := buildCLI(runExtract)
 
 	// Close pipe and restore stderr
 	_ = w.Close()
@@ -353,7 +362,7 @@ func TestBuildCLIWithCustomExtractor(t *testing.T) {
 	}
 
 	cmd := buildCLI(extractorFunc)
-	require.Len(t, cmd.Commands, 1)
+	require.Len(t, cmd.Commands, 7)
 
 	extractCmd := cmd.Commands[0]
 	ctx := context.Background()
@@ -430,15 +439,7 @@ func TestMainFunctionRealWorldScenario(t *testing.T) {
 	}
 
 	// Create and run CLI command
-	cliCmd := buildCLI(func(command *cli.Command) error {
-		return i18n.NewExtractor(
-			command.String("dir"),
-			command.String("out"),
-			command.String("pkg"),
-			command.String("gofile"),
-			command.StringSlice("ext")...,
-		).Extract()
-	})
+	cliCmd := buildCLI(runExtract)
 
 	ctx := context.Background()
 	err := cliCmd.Run(ctx, os.Args)
@@ -490,15 +491,7 @@ func TestMainFunctionNoOutputPaths(t *testing.T) {
 	}
 
 	// Create and run CLI command
-	cliCmd := buildCLI(func(command *cli.Command) error {
-		return i18n.NewExtractor(
-			command.String("dir"),
-			command.String("out"),
-			command.String("pkg"),
-			command.String("gofile"),
-			command.StringSlice("ext")...,
-		).Extract()
-	})
+	cliCmd := buildCLI(runExtract)
 
 	ctx := context.Background()
 	err := cliCmd.Run(ctx, os.Args)
@@ -527,7 +520,7 @@ func createTempFile(t *testing.T, dir, name, content string) string {
 // BenchmarkMainFunction benchmarks the main function setup
 func BenchmarkMainFunction(b *testing.B) {
 	extractorFunc := func(cmd *cli.Command) error {
-		return i18n.NewExtractor(".", "", "main", "gotext_stub.go", ".html").Extract()
+		return i18n.NewExtractor(i18n.WithExts(".html")).Extract()
 	}
 
 	b.ResetTimer()
@@ -535,3 +528,196 @@ func BenchmarkMainFunction(b *testing.B) {
 		_ = buildCLI(extractorFunc)
 	}
 }
+
+// TestRunExtractCustomFuncs tests extraction with custom delimiters and function names
+func TestRunExtractWriter(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestTemplate(t, tempDir, "page.html", `{{ T .Lang "Hello" }}`)
+
+	var out bytes.Buffer
+	cliCmd := buildCLI(runExtract)
+	cliCmd.Writer = &out
+
+	err := cliCmd.Run(context.Background(), []string{
+		"i18n", "extract",
+		"--dir", tempDir,
+		"--out", filepath.Join(tempDir, "messages.json"),
+		"--gofile", filepath.Join(tempDir, "gotext_stub.go"),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Scanned 1 templates")
+	assert.Contains(t, out.String(), "Wrote 1 messages")
+}
+
+func TestRunExtractStrict(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestTemplate(t, tempDir, "page.html", `{{ T .Lang .Key }}`)
+	outputFile := filepath.Join(tempDir, "messages.json")
+
+	var out bytes.Buffer
+	cliCmd := buildCLI(runExtract)
+	cliCmd.Writer = &out
+
+	args := []string{
+		"i18n", "extract",
+		"--dir", tempDir,
+		"--out", outputFile,
+		"--gofile", filepath.Join(tempDir, "gotext_stub.go"),
+	}
+
+	require.NoError(t, cliCmd.Run(context.Background(), args))
+	assert.Contains(t, out.String(), "warning: page.html:1:7: dynamic message key .Key cannot be extracted")
+
+	require.NoError(t, os.Remove(outputFile))
+	err := cliCmd.Run(context.Background(), append(args, "--strict"))
+	assert.EqualError(t, err, "strict mode: 1 extraction warnings")
+	assert.NoFileExists(t, outputFile)
+}
+
+func TestRunExtractCheck(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestTemplate(t, tempDir, "page.html", `{{ T .Lang "Hello" }}`)
+
+	var out bytes.Buffer
+	cliCmd := buildCLI(runExtract)
+	cliCmd.Writer = &out
+
+	args := []string{
+		"i18n", "extract",
+		"--dir", tempDir,
+		"--out", filepath.Join(tempDir, "messages.json"),
+		"--gofile", filepath.Join(tempDir, "gotext_stub.go"),
+	}
+
+	err := cliCmd.Run(context.Background(), append(args, "--check"))
+	assert.ErrorIs(t, err, i18n.ErrOutOfDate)
+	assert.Contains(t, out.String(), `+      "id": "Hello",`)
+	assert.NoFileExists(t, filepath.Join(tempDir, "messages.json"))
+
+	require.NoError(t, cliCmd.Run(context.Background(), args))
+	require.NoError(t, cliCmd.Run(context.Background(), append(args, "--check")))
+}
+
+func TestRunExtractWithoutGoFile(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestTemplate(t, tempDir, "page.html", `{{ T .Lang "Hello" }}`)
+
+	t.Chdir(tempDir)
+
+	cliCmd := buildCLI(runExtract)
+	err := cliCmd.Run(context.Background(), []string{"i18n", "extract", "--out", "messages.json", "--gofile", ""})
+	require.NoError(t, err)
+
+	assert.FileExists(t, "messages.json")
+	assert.NoFileExists(t, "gotext_stub.go")
+}
+
+func TestRunExtractCustomFuncs(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestTemplate(t, tempDir, "theme.html", `[[ tr .Lang "Hello" ]] [[ trp "Items" .Lang ]] {{ T .Lang "Ignored" }}`)
+	outputFile := filepath.Join(tempDir, "messages.json")
+	goFile := filepath.Join(tempDir, "gotext_stub.go")
+
+	cliCmd := buildCLI(runExtract)
+	err := cliCmd.Run(context.Background(), []string{
+		"i18n", "extract",
+		"--dir", tempDir,
+		"--out", outputFile,
+		"--gofile", goFile,
+		"--ext", ".html",
+		"--left-delim", "[[",
+		"--right-delim", "]]",
+		"--func", "tr",
+		"--func", "trp:0",
+	})
+	require.NoError(t, err)
+
+	jsonContent, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(jsonContent), `"Hello"`)
+	assert.Contains(t, string(jsonContent), `"Items"`)
+	assert.NotContains(t, string(jsonContent), "Ignored")
+
+	err = cliCmd.Run(context.Background(), []string{"i18n", "extract", "--dir", tempDir, "--gofile", goFile, "--func", "tr:x"})
+	assert.Error(t, err)
+}
+
+// TestRunExtractConfigFile tests that settings are read from a config file and overridden by flags
+func TestRunExtractConfigFile(t *testing.T) {
+	tempDir := t.TempDir()
+	themes := filepath.Join(tempDir, "themes")
+	require.NoError(t, os.MkdirAll(themes, 0755))
+	createTestTemplate(t, themes, "page.gohtml", `[[ tr .Lang "From config" ]]`)
+	createTempFile(t, tempDir, "i18n.yaml", `
+dir: themes
+out: messages.json
+gofile: gotext_stub.go
+pkg: locales
+ext: [.gohtml]
+left_delim: "[["
+right_delim: "]]"
+funcs: [tr]
+source_language: it
+`)
+
+	cliCmd := buildCLI(runExtract)
+	err := cliCmd.Run(context.Background(), []string{"i18n", "extract", "--config", filepath.Join(tempDir, "i18n.yaml")})
+	require.NoError(t, err)
+
+	jsonContent, err := os.ReadFile(filepath.Join(tempDir, "messages.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(jsonContent), "From config")
+
+	goContent, err := os.ReadFile(filepath.Join(tempDir, "gotext_stub.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(goContent), "package locales")
+	assert.Contains(t, string(goContent), `message.NewPrinter(language.MustParse("it"))`)
+
+	// flags win over the config file
+	err = cliCmd.Run(context.Background(), []string{"i18n", "extract", "--config", filepath.Join(tempDir, "i18n.yaml"), "--pkg", "override"})
+	require.NoError(t, err)
+
+	goContent, err = os.ReadFile(filepath.Join(tempDir, "gotext_stub.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(goContent), "package override")
+
+	err = cliCmd.Run(context.Background(), []string{"i18n", "extract", "--config", filepath.Join(tempDir, "i18n.yaml"), "--source-language", "de_CH"})
+	require.NoError(t, err)
+
+	jsonContent, err = os.ReadFile(filepath.Join(tempDir, "messages.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(jsonContent), `"language": "de-CH"`)
+
+	err = cliCmd.Run(context.Background(), []string{"i18n", "extract", "--config", filepath.Join(tempDir, "i18n.yaml"), "--source-language", "not a language"})
+	assert.ErrorContains(t, err, "source language")
+
+	err = cliCmd.Run(context.Background(), []string{"i18n", "extract", "--config", filepath.Join(tempDir, "missing.yaml")})
+	assert.Error(t, err)
+}
+
+// TestRunExtractDomains tests writing one messages file per domain mapped with --domain
+func TestRunExtractDomains(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "admin"), 0755))
+	createTestTemplate(t, tempDir, "index.html", `{{ T .Lang "Home" }}`)
+	createTestTemplate(t, filepath.Join(tempDir, "admin"), "users.html", `{{ T .Lang "Save" }}`)
+	outputFile := filepath.Join(tempDir, "messages.json")
+
+	cliCmd := buildCLI(runExtract)
+	err := cliCmd.Run(context.Background(), []string{"i18n", "extract", "--dir", tempDir, "--out", outputFile, "--gofile", "", "--domain", "admin=admin/**"})
+	require.NoError(t, err)
+
+	messages, err := i18n.ReadMessagesFile(outputFile)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Home", messages[0].ID)
+
+	messages, err = i18n.ReadMessagesFile(filepath.Join(tempDir, "messages.admin.json"))
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Save", messages[0].ID)
+	assert.Equal(t, "admin", messages[0].Domain)
+
+	err = cliCmd.Run(context.Background(), []string{"i18n", "extract", "--dir", tempDir, "--out", outputFile, "--domain", "admin"})
+	assert.ErrorContains(t, err, `invalid domain "admin": expected name=glob`)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/gowool/i18n"
+)
+
+func update() *cli.Command {
+	return &cli.Command{
+		Name:      "update",
+		Usage:     "Merge extracted messages into gotext translation files",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "messages",
+				Value: "messages.json",
+				Usage: "messages JSON written by extract --out",
+			},
+			&cli.StringFlag{
+				Name:  "dir",
+				Value: "locales",
+				Usage: "directory of the *.gotext.json translation files to update",
+			},
+			&cli.StringSliceFlag{
+				Name:  "lang",
+				Usage: "language to create a translation file for if it has none, as <dir>/<lang>/messages.gotext.json (repeatable)",
+			},
+		},
+		Action: func(_ context.Context, command *cli.Command) error {
+			return updateTranslations(command.String("messages"), command.String("dir"), command.StringSlice("lang"), writer(command))
+		},
+	}
+}
+
+func updateTranslations(in, dir string, langs []string, w io.Writer) error {
+	messages, err := i18n.ReadMessagesFile(in)
+	if err != nil {
+		return err
+	}
+
+	targets, err := readGotextFiles(dir)
+	if err != nil && (!errors.Is(err, fs.ErrNotExist) || len(langs) == 0) {
+		return err
+	}
+
+	for _, lang := range langs {
+		lang = normalizeLanguage(lang)
+		if slices.ContainsFunc(targets, func(t *gotextFile) bool { return normalizeLanguage(t.file.Language) == lang }) {
+			continue
+		}
+		targets = append(targets, &gotextFile{
+			path: filepath.Join(dir, lang, "messages.gotext.json"),
+			file: &i18n.TranslationFile{Language: lang},
+		})
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no translation files in %s, add languages with --lang", dir)
+	}
+
+	for _, t := range targets {
+		res, err := t.file.Update(messages)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(t.path); err == nil && *res == (i18n.UpdateResult{}) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+			return err
+		}
+		if err := i18n.WriteTranslationFile(t.path, t.file); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "Updated %s: %d added, %d changed (%d fuzzy), %d removed\n", t.path, res.Added, res.Changed, res.Fuzzy, res.Removed)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gowool/i18n"
+)
+
+// TestUpdateCommandStructure tests the structure of the update command
+func TestUpdateCommandStructure(t *testing.T) {
+	cmd := update()
+
+	assert.Equal(t, "update", cmd.Name)
+	assert.Equal(t, "Merge extracted messages into gotext translation files", cmd.Usage)
+	require.Len(t, cmd.Flags, 3)
+
+	flagNames := make([]string, len(cmd.Flags))
+	for i, f := range cmd.Flags {
+		flagNames[i] = f.Names()[0]
+	}
+	assert.Equal(t, []string{"messages", "dir", "lang"}, flagNames)
+}
+
+// TestUpdateCommand tests merging extracted messages into gotext files
+func TestUpdateCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	locales := filepath.Join(tempDir, "locales")
+	require.NoError(t, os.MkdirAll(filepath.Join(locales, "de-DE"), 0755))
+	createTempFile(t, filepath.Join(locales, "de-DE"), "messages.gotext.json", deGotextFile)
+	messages := createTempFile(t, tempDir, "messages.json", `{
+		"language": "en",
+		"messages": [
+			{"id": "Hello %s", "positions": ["index.html:1:7"]},
+			{"id": "Save", "positions": ["index.html:2:7"]}
+		]
+	}`)
+
+	var buf bytes.Buffer
+	cmd := buildCLI(nil)
+	cmd.Writer = &buf
+
+	err := cmd.Run(context.Background(), []string{"i18n", "update", "--messages", messages, "--dir", locales, "--lang", "de_DE", "--lang", "fr"})
+	require.NoError(t, err)
+
+	de := filepath.Join(locales, "de-DE", "messages.gotext.json")
+	fr := filepath.Join(locales, "fr", "messages.gotext.json")
+	assert.Contains(t, buf.String(), "Updated "+de+": 1 added, 0 changed (0 fuzzy), 1 removed")
+	assert.Contains(t, buf.String(), "Updated "+fr+": 2 added, 0 changed (0 fuzzy), 0 removed")
+
+	f, err := i18n.ReadTranslationFile(de)
+	require.NoError(t, err)
+	require.Len(t, f.Messages, 2)
+	assert.Equal(t, "Hallo {Arg_1}", f.Messages[0].Translation.Msg)
+	assert.Equal(t, "Save", f.Messages[1].ID)
+	assert.Equal(t, "index.html:2:7", f.Messages[1].Position)
+
+	f, err = i18n.ReadTranslationFile(fr)
+	require.NoError(t, err)
+	assert.Equal(t, "fr", f.Language)
+	assert.Len(t, f.Messages, 2)
+
+	t.Run("Changed sources are fuzzy", func(t *testing.T) {
+		createTempFile(t, filepath.Join(locales, "de-DE"), "messages.gotext.json", `{
+			"language": "de-DE",
+			"messages": [{"id": "checkout.pay", "message": "Pay {amount}", "translation": "Zahle {amount}"}]
+		}`)
+		createTempFile(t, tempDir, "messages.json", `{"messages": [{"id": "checkout.pay", "message": "Pay {total}"}]}`)
+		buf.Reset()
+
+		err := cmd.Run(context.Background(), []string{"i18n", "update", "--messages", messages, "--dir", locales})
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "Updated "+de+": 0 added, 1 changed (1 fuzzy), 0 removed")
+
+		f, err := i18n.ReadTranslationFile(de)
+		require.NoError(t, err)
+		assert.Equal(t, "Pay {total}", f.Messages[0].Message.Msg)
+		assert.True(t, f.Messages[0].Fuzzy)
+
+		buf.Reset()
+		require.NoError(t, cmd.Run(context.Background(), []string{"i18n", "update", "--messages", messages, "--dir", locales}))
+		assert.NotContains(t, buf.String(), de)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		err := cmd.Run(context.Background(), []string{"i18n", "update", "--messages", messages, "--dir", filepath.Join(tempDir, "missing")})
+		assert.Error(t, err)
+
+		err = cmd.Run(context.Background(), []string{"i18n", "update", "--messages", filepath.Join(tempDir, "missing.json"), "--dir", locales})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/gowool/i18n"
+)
+
+func validate() *cli.Command {
+	return &cli.Command{
+		Name:      "validate",
+		Usage:     "Validate placeholders of translation catalogs",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "dir",
+				Value: "locales",
+				Usage: "directory to scan for translation files (*.gotext.json, *.yaml, *.toml)",
+			},
+		},
+		Action: func(_ context.Context, command *cli.Command) error {
+			return validateDir(command.String("dir"))
+		},
+	}
+}
+
+func validateDir(dir string) error {
+	files, err := i18n.ReadTranslationDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, f := range files {
+		if err := f.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+// TestValidateCommandStructure tests the structure of the validate command
+func TestValidateCommandStructure(t *testing.T) {
+	cmd := validate()
+
+	assert.Equal(t, "validate", cmd.Name)
+	assert.Equal(t, "Validate placeholders of translation catalogs", cmd.Usage)
+	require.Len(t, cmd.Flags, 1)
+
+	dirFlag, ok := cmd.Flags[0].(*cli.StringFlag)
+	require.True(t, ok)
+	assert.Equal(t, "dir", dirFlag.Name)
+	assert.Equal(t, "locales", dirFlag.Value)
+}
+
+// TestValidateCommand tests validation of translation files found in a directory
+func TestValidateCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "de-DE"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "it-IT"), 0755))
+
+	createTempFile(t, filepath.Join(tempDir, "de-DE"), "messages.gotext.json", `{
+		"language": "de-DE",
+		"messages": [{"id": "Hello {name}", "message": "Hello {name}", "translation": "Hallo {name}"}]
+	}`)
+	createTempFile(t, filepath.Join(tempDir, "it-IT"), "messages.gotext.json", `{
+		"language": "it-IT",
+		"messages": [{"id": "Hello {name}", "message": "Hello {name}", "translation": "Ciao {nome}"}]
+	}`)
+	createTempFile(t, tempDir, "ignored.json", `not json`)
+
+	cmd := buildCLI(nil)
+
+	err := cmd.Run(context.Background(), []string{"i18n", "validate", "--dir", filepath.Join(tempDir, "de-DE")})
+	assert.NoError(t, err)
+
+	err = cmd.Run(context.Background(), []string{"i18n", "validate", "--dir", tempDir})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `it-IT: "Hello {name}": missing placeholders {name}, unknown placeholders {nome}`)
+
+	err = cmd.Run(context.Background(), []string{"i18n", "validate", "--dir", filepath.Join(tempDir, "missing")})
+	assert.Error(t, err)
+}
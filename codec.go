@@ -0,0 +1,143 @@
+package i18n
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// rePluralCase matches the cases of a plural message: the CLDR categories
+// and explicit values such as "=0" or "<10".
+var rePluralCase = regexp.MustCompile(`^(zero|one|two|few|many|other|[=<]\d+)$`)
+
+// Codec reads and writes translation files in one format.
+//
+// Formats that map keys to translations, such as YAML and TOML, carry no
+// language and no source text: the language is taken from the file name and
+// every message is identified by its key.
+type Codec interface {
+	Decode(data []byte) (*TranslationFile, error)
+	Encode(f *TranslationFile) ([]byte, error)
+}
+
+// GotextCodec is the gotext JSON format (*.gotext.json).
+type GotextCodec struct{}
+
+func (GotextCodec) Decode(data []byte) (*TranslationFile, error) {
+	var f TranslationFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (GotextCodec) Encode(f *TranslationFile) ([]byte, error) {
+	raw, err := json.MarshalIndent(f, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(raw, '\n'), nil
+}
+
+// CodecFor returns the codec of a translation file by its extension:
+// .gotext.json, .yaml, .yml or .toml.
+func CodecFor(name string) (Codec, bool) {
+	switch {
+	case strings.HasSuffix(name, ".gotext.json"):
+		return GotextCodec{}, true
+	case strings.HasSuffix(name, ".yaml"), strings.HasSuffix(name, ".yml"):
+		return YAMLCodec{}, true
+	case strings.HasSuffix(name, ".toml"):
+		return TOMLCodec{}, true
+	}
+	return nil, false
+}
+
+// pathLanguage returns the language a translation file name refers to: the
+// last dot-separated part of its base name, as in de.yaml or messages.de.toml,
+// or else the name of its directory, as in de/messages.yaml.
+func pathLanguage(name string) string {
+	name = path.Clean(strings.ReplaceAll(name, `\`, "/"))
+
+	base := path.Base(name)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	if i := strings.LastIndexByte(base, '.'); i >= 0 {
+		base = base[i+1:]
+	}
+	if tag, err := language.Parse(base); err == nil {
+		return tag.String()
+	}
+
+	if tag, err := language.Parse(path.Base(path.Dir(name))); err == nil {
+		return tag.String()
+	}
+	return ""
+}
+
+// keyedTranslation returns the message of a key-value format.
+func keyedTranslation(key string, text Text, comment string) *Translation {
+	return &Translation{ID: key, Key: key, Translation: text, Comment: comment}
+}
+
+// translationKey returns the key a message is written with in key-value
+// formats: the runtime key of gotext messages, otherwise the ID.
+func translationKey(m *Translation) string {
+	if m.Key != "" {
+		return m.Key
+	}
+	return m.ID
+}
+
+// keyPath returns the nesting of key in key-value formats: stable dotted
+// keys are nested, any other key is a single entry. Keys ending in a plural
+// case, such as "count.other", are not nested so they cannot be taken for a
+// plural message.
+func keyPath(key string) []string {
+	if !reKey.MatchString(key) {
+		return []string{key}
+	}
+
+	path := strings.Split(key, ".")
+	if rePluralCase.MatchString(path[len(path)-1]) {
+		return []string{key}
+	}
+	return path
+}
+
+// isPlural reports whether keys are the cases of a plural message rather
+// than nested message keys.
+func isPlural(keys []string) bool {
+	other := false
+	for _, k := range keys {
+		if !rePluralCase.MatchString(k) {
+			return false
+		}
+		other = other || k == "other"
+	}
+	return other
+}
+
+// commentLines returns the text of "#" comment lines.
+func commentLines(comment string) string {
+	var lines []string
+	for line := range strings.SplitSeq(comment, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line = strings.TrimPrefix(line, "#")
+		lines = append(lines, strings.TrimPrefix(line, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commentText returns comment as "#" comment lines.
+func commentText(comment string) string {
+	if comment == "" {
+		return ""
+	}
+	return "# " + strings.ReplaceAll(comment, "\n", "\n# ")
+}
@@ -0,0 +1,281 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlFile = `# shown on the checkout page
+checkout:
+  pay: Jetzt bezahlen
+  # the cancel button
+  # of the form
+  cancel: Abbrechen # short
+"Hello %s": Hallo %s
+"%d items":
+  one: ein Artikel
+  other: '%d Artikel'
+nav.home: Startseite
+`
+
+const tomlFile = `# shown on the checkout page
+checkout.pay = "Jetzt bezahlen"
+"Hello %s" = "Hallo %s"
+"%d items" = { one = "ein Artikel", other = "%d Artikel" }
+
+[nav]
+# the start page
+home = 'Startseite'
+about = """
+Über
+uns"""
+
+# the back link
+back = "Zurück"
+
+# a comment of nothing
+
+
+`
+
+func TestCodecFor(t *testing.T) {
+	for name, want := range map[string]Codec{
+		"locales/de/messages.gotext.json": GotextCodec{},
+		"de.yaml":                         YAMLCodec{},
+		"de.yml":                          YAMLCodec{},
+		"messages.de.toml":                TOMLCodec{},
+	} {
+		codec, ok := CodecFor(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, want, codec, name)
+	}
+
+	_, ok := CodecFor("messages.json")
+	assert.False(t, ok)
+}
+
+func TestPathLanguage(t *testing.T) {
+	assert.Equal(t, "de", pathLanguage("locales/de.yaml"))
+	assert.Equal(t, "pt-BR", pathLanguage("messages.pt-BR.toml"))
+	assert.Equal(t, "fr", pathLanguage("locales/fr/messages.yaml"))
+	assert.Equal(t, "it", pathLanguage(`locales\it\messages.yaml`))
+	assert.Empty(t, pathLanguage("translations.yaml"))
+}
+
+func TestYAMLCodec(t *testing.T) {
+	f, err := YAMLCodec{}.Decode([]byte(yamlFile))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Translation{
+		{ID: "checkout.pay", Key: "checkout.pay", Translation: Text{Msg: "Jetzt bezahlen"}, Comment: "shown on the checkout page"},
+		{ID: "checkout.cancel", Key: "checkout.cancel", Translation: Text{Msg: "Abbrechen"}, Comment: "shown on the checkout page\nthe cancel button\nof the form\nshort"},
+		{ID: "Hello %s", Key: "Hello %s", Translation: Text{Msg: "Hallo %s"}},
+		{ID: "%d items", Key: "%d items", Translation: Text{Cases: map[string]string{"one": "ein Artikel", "other": "%d Artikel"}}},
+		{ID: "nav.home", Key: "nav.home", Translation: Text{Msg: "Startseite"}},
+	}, f.Messages)
+
+	t.Run("Round trip", func(t *testing.T) {
+		raw, err := YAMLCodec{}.Encode(f)
+		require.NoError(t, err)
+		assert.Equal(t, `# shown on the checkout page
+checkout:
+  pay: Jetzt bezahlen
+  # the cancel button
+  # of the form
+  # short
+  cancel: Abbrechen
+Hello %s: Hallo %s
+'%d items':
+  one: ein Artikel
+  other: '%d Artikel'
+nav:
+  home: Startseite
+`, string(raw))
+
+		decoded, err := YAMLCodec{}.Decode(raw)
+		require.NoError(t, err)
+		assert.Equal(t, f.Messages, decoded.Messages)
+	})
+
+	t.Run("Round trip of the documented example", func(t *testing.T) {
+		const example = `# shown on the checkout page
+checkout:
+  pay: Jetzt bezahlen
+'%d items':
+  one: ein Artikel
+  other: '%d Artikel'
+`
+		f, err := YAMLCodec{}.Decode([]byte(example))
+		require.NoError(t, err)
+		assert.Equal(t, "shown on the checkout page", f.Messages[0].Comment)
+
+		raw, err := YAMLCodec{}.Encode(f)
+		require.NoError(t, err)
+		assert.Equal(t, example, string(raw))
+	})
+
+	t.Run("Nested mapping comments", func(t *testing.T) {
+		raw, err := YAMLCodec{}.Encode(&TranslationFile{Messages: []*Translation{
+			{ID: "a.b.c", Translation: Text{Msg: "x"}, Comment: "outer\ninner"},
+			{ID: "a.b.d", Translation: Text{Msg: "y"}, Comment: "outer\ninner\nown"},
+			{ID: "a.e", Translation: Text{Msg: "z"}, Comment: "other"},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, "a:\n  # outer\n  # inner\n  b:\n    c: x\n    # own\n    d: y\n  # other\n  e: z\n", string(raw))
+
+		f, err := YAMLCodec{}.Decode(raw)
+		require.NoError(t, err)
+		require.Len(t, f.Messages, 3)
+		assert.Equal(t, "outer\ninner", f.Messages[0].Comment)
+		assert.Equal(t, "outer\ninner\nown", f.Messages[1].Comment)
+		assert.Equal(t, "other", f.Messages[2].Comment)
+	})
+
+	t.Run("Conflicting keys stay flat", func(t *testing.T) {
+		raw, err := YAMLCodec{}.Encode(&TranslationFile{Messages: []*Translation{
+			{ID: "a.b", Translation: Text{Msg: "x"}},
+			{ID: "a.b.c", Translation: Text{Msg: "y"}},
+			{ID: "count.other", Translation: Text{Msg: "z"}},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, "a:\n  b: x\na.b.c: y\ncount.other: z\n", string(raw))
+
+		f, err := YAMLCodec{}.Decode(raw)
+		require.NoError(t, err)
+		require.Len(t, f.Messages, 3)
+		assert.Equal(t, "a.b.c", f.Messages[1].ID)
+		assert.Equal(t, Text{Msg: "z"}, f.Messages[2].Translation)
+	})
+
+	t.Run("Conflicting messages", func(t *testing.T) {
+		_, err := YAMLCodec{}.Encode(&TranslationFile{Messages: []*Translation{{ID: "Hi"}, {ID: "Hi"}}})
+		assert.ErrorContains(t, err, `message "Hi" conflicts with another message`)
+
+		_, err = YAMLCodec{}.Encode(&TranslationFile{Messages: []*Translation{{ID: "a.b"}, {ID: "a"}}})
+		assert.ErrorContains(t, err, `message "a" conflicts with another message`)
+	})
+
+	t.Run("Invalid documents", func(t *testing.T) {
+		_, err := YAMLCodec{}.Decode([]byte("- a\n- b\n"))
+		assert.ErrorContains(t, err, "expected a mapping of messages")
+
+		_, err = YAMLCodec{}.Decode([]byte("a:\n  - b\n"))
+		assert.ErrorContains(t, err, `"a": expected a message`)
+
+		f, err := YAMLCodec{}.Decode(nil)
+		require.NoError(t, err)
+		assert.Empty(t, f.Messages)
+	})
+}
+
+func TestTOMLCodec(t *testing.T) {
+	f, err := TOMLCodec{}.Decode([]byte(tomlFile))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Translation{
+		{ID: "checkout.pay", Key: "checkout.pay", Translation: Text{Msg: "Jetzt bezahlen"}, Comment: "shown on the checkout page"},
+		{ID: "Hello %s", Key: "Hello %s", Translation: Text{Msg: "Hallo %s"}},
+		{ID: "%d items", Key: "%d items", Translation: Text{Cases: map[string]string{"one": "ein Artikel", "other": "%d Artikel"}}},
+		{ID: "nav.home", Key: "nav.home", Translation: Text{Msg: "Startseite"}, Comment: "the start page"},
+		{ID: "nav.about", Key: "nav.about", Translation: Text{Msg: "Über\nuns"}},
+		{ID: "nav.back", Key: "nav.back", Translation: Text{Msg: "Zurück"}, Comment: "the back link"},
+	}, f.Messages)
+
+	t.Run("Round trip", func(t *testing.T) {
+		raw, err := TOMLCodec{}.Encode(f)
+		require.NoError(t, err)
+		assert.Equal(t, `# shown on the checkout page
+checkout.pay = "Jetzt bezahlen"
+"Hello %s" = "Hallo %s"
+"%d items" = { one = "ein Artikel", other = "%d Artikel" }
+# the start page
+nav.home = "Startseite"
+nav.about = "Über\nuns"
+# the back link
+nav.back = "Zurück"
+`, string(raw))
+
+		decoded, err := TOMLCodec{}.Decode(raw)
+		require.NoError(t, err)
+		assert.Equal(t, f.Messages, decoded.Messages)
+	})
+
+	t.Run("Round trip of the documented example", func(t *testing.T) {
+		const example = `# shown on the checkout page
+checkout:
+  pay: Jetzt bezahlen
+'%d items':
+  one: ein Artikel
+  other: '%d Artikel'
+`
+		f, err := YAMLCodec{}.Decode([]byte(example))
+		require.NoError(t, err)
+		assert.Equal(t, "shown on the checkout page", f.Messages[0].Comment)
+
+		raw, err := YAMLCodec{}.Encode(f)
+		require.NoError(t, err)
+		assert.Equal(t, example, string(raw))
+	})
+
+	t.Run("Nested mapping comments", func(t *testing.T) {
+		raw, err := YAMLCodec{}.Encode(&TranslationFile{Messages: []*Translation{
+			{ID: "a.b.c", Translation: Text{Msg: "x"}, Comment: "outer\ninner"},
+			{ID: "a.b.d", Translation: Text{Msg: "y"}, Comment: "outer\ninner\nown"},
+			{ID: "a.e", Translation: Text{Msg: "z"}, Comment: "other"},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, "a:\n  # outer\n  # inner\n  b:\n    c: x\n    # own\n    d: y\n  # other\n  e: z\n", string(raw))
+
+		f, err := YAMLCodec{}.Decode(raw)
+		require.NoError(t, err)
+		require.Len(t, f.Messages, 3)
+		assert.Equal(t, "outer\ninner", f.Messages[0].Comment)
+		assert.Equal(t, "outer\ninner\nown", f.Messages[1].Comment)
+		assert.Equal(t, "other", f.Messages[2].Comment)
+	})
+
+	t.Run("Conflicting keys stay flat", func(t *testing.T) {
+		raw, err := TOMLCodec{}.Encode(&TranslationFile{Messages: []*Translation{
+			{ID: "a.b", Translation: Text{Msg: "x"}},
+			{ID: "a.b.c", Translation: Text{Msg: "y"}, Comment: "flat"},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, "a.b = \"x\"\n# flat\n\"a.b.c\" = \"y\"\n", string(raw))
+
+		f, err := TOMLCodec{}.Decode(raw)
+		require.NoError(t, err)
+		require.Len(t, f.Messages, 2)
+		assert.Equal(t, "a.b.c", f.Messages[1].ID)
+		assert.Equal(t, "flat", f.Messages[1].Comment)
+	})
+
+	t.Run("Conflicting messages", func(t *testing.T) {
+		_, err := TOMLCodec{}.Encode(&TranslationFile{Messages: []*Translation{{ID: "Hi"}, {ID: "Hi"}}})
+		assert.ErrorContains(t, err, `message "Hi" conflicts with another message`)
+
+		_, err = TOMLCodec{}.Encode(&TranslationFile{Messages: []*Translation{{ID: "a.b"}, {ID: "a"}}})
+		assert.ErrorContains(t, err, `message "a" conflicts with another message`)
+	})
+
+	t.Run("Invalid documents", func(t *testing.T) {
+		_, err := TOMLCodec{}.Decode([]byte("a = 1\n"))
+		assert.ErrorContains(t, err, `"a": expected a message`)
+
+		_, err = TOMLCodec{}.Decode([]byte("a = \n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestGotextCodec(t *testing.T) {
+	f, err := GotextCodec{}.Decode([]byte(gotextFile))
+	require.NoError(t, err)
+
+	raw, err := GotextCodec{}.Encode(f)
+	require.NoError(t, err)
+
+	decoded, err := GotextCodec{}.Decode(raw)
+	require.NoError(t, err)
+	assert.Equal(t, f, decoded)
+}
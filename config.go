@@ -0,0 +1,376 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the name of the extraction config file looked up by FindConfig.
+const ConfigFile = "i18n.yaml"
+
+// DefaultExts are the template extensions scanned by default.
+var DefaultExts = []string{".html", ".htm", ".tmpl", ".gohtml", ".txt", ".tpl"}
+
+// ExtractorConfig holds extraction settings, usually loaded from i18n.yaml.
+// Zero values keep the Extractor defaults.
+type ExtractorConfig struct {
+	Dir        string   `yaml:"dir"`         // directory to scan for templates
+	Out        string   `yaml:"out"`         // output JSON with found messages
+	Pkg        string   `yaml:"pkg"`         // package name of the generated Go file
+	GoFile     string   `yaml:"gofile"`      // synthetic Go file for gotext
+	Exts       []string `yaml:"ext"`         // template extensions to consider
+	LeftDelim  string   `yaml:"left_delim"`  // left template action delimiter
+	RightDelim string   `yaml:"right_delim"` // right template action delimiter
+	Funcs      []Func   `yaml:"funcs"`       // translation functions
+
+	Include     []string `yaml:"include"`      // doublestar globs of templates to scan, relative to Dir
+	Exclude     []string `yaml:"exclude"`      // doublestar globs of files and directories to skip
+	NoGitignore bool     `yaml:"no_gitignore"` // scan files ignored by .gitignore
+	Hidden      bool     `yaml:"hidden"`       // scan hidden files and directories
+	Workers     int      `yaml:"workers"`      // concurrent template scanners, GOMAXPROCS by default
+	Cache       string   `yaml:"cache"`        // incremental extraction cache file, e.g. .i18n-cache
+	Strict      bool     `yaml:"strict"`       // fail on extraction warnings
+
+	FilePositions bool `yaml:"file_positions"` // list positions as file names without line and column
+
+	SourceLanguage language.Tag `yaml:"source_language"` // language the messages are written in
+
+	Domains map[string][]string `yaml:"domains"` // doublestar globs of the templates of each domain, relative to Dir
+	Modules []string            `yaml:"modules"` // Go modules whose templates are scanned too
+}
+
+// ExtractorOption configures an Extractor.
+type ExtractorOption func(*Extractor)
+
+// WithConfig applies every non-zero setting of cfg.
+func WithConfig(cfg ExtractorConfig) ExtractorOption {
+	return func(e *Extractor) {
+		if cfg.Dir != "" {
+			e.dir = cfg.Dir
+		}
+		if cfg.Out != "" {
+			e.out = cfg.Out
+		}
+		if cfg.Pkg != "" {
+			e.pkg = cfg.Pkg
+		}
+		if cfg.GoFile != "" {
+			e.goFile = cfg.GoFile
+		}
+		if len(cfg.Exts) > 0 {
+			WithExts(cfg.Exts...)(e)
+		}
+		if cfg.LeftDelim != "" {
+			e.left = cfg.LeftDelim
+		}
+		if cfg.RightDelim != "" {
+			e.right = cfg.RightDelim
+		}
+		if len(cfg.Funcs) > 0 {
+			e.funcs = cfg.Funcs
+		}
+		if len(cfg.Include) > 0 {
+			e.include = cfg.Include
+		}
+		if len(cfg.Exclude) > 0 {
+			e.exclude = cfg.Exclude
+		}
+		if cfg.NoGitignore {
+			e.noGitignore = true
+		}
+		if cfg.Hidden {
+			e.hidden = true
+		}
+		if cfg.Workers > 0 {
+			e.workers = cfg.Workers
+		}
+		if cfg.Cache != "" {
+			e.cache = cfg.Cache
+		}
+		if cfg.Strict {
+			e.strict = true
+		}
+		if cfg.FilePositions {
+			e.filePositions = true
+		}
+		if cfg.SourceLanguage != language.Und {
+			e.sourceLang = cfg.SourceLanguage
+		}
+		if len(cfg.Domains) > 0 {
+			e.domains = cfg.Domains
+		}
+		if len(cfg.Modules) > 0 {
+			e.modules = cfg.Modules
+		}
+	}
+}
+
+// WithDir sets the directory to scan for templates.
+func WithDir(dir string) ExtractorOption {
+	return func(e *Extractor) {
+		e.dir = dir
+	}
+}
+
+// WithOut sets the JSON file the found messages are written to.
+func WithOut(path string) ExtractorOption {
+	return func(e *Extractor) {
+		e.out = path
+	}
+}
+
+// WithPkg sets the package name of the generated Go file.
+func WithPkg(name string) ExtractorOption {
+	return func(e *Extractor) {
+		e.pkg = name
+	}
+}
+
+// WithGoFile sets the synthetic Go file generated for gotext. An empty path
+// skips it, e.g. when the catalog is built with GenerateCatalog.
+func WithGoFile(path string) ExtractorOption {
+	return func(e *Extractor) {
+		e.goFile = path
+	}
+}
+
+// WithExts sets the template extensions to consider.
+func WithExts(exts ...string) ExtractorOption {
+	return func(e *Extractor) {
+		e.exts = make(map[string]struct{}, len(exts))
+		for _, ext := range exts {
+			e.exts[ext] = struct{}{}
+		}
+	}
+}
+
+// WithDelims sets the template action delimiters, "{{" and "}}" by default.
+func WithDelims(left, right string) ExtractorOption {
+	return func(e *Extractor) {
+		e.left, e.right = left, right
+	}
+}
+
+// WithFuncs sets the translation functions to look for, DefaultFuncs by default.
+func WithFuncs(funcs ...Func) ExtractorOption {
+	return func(e *Extractor) {
+		e.funcs = funcs
+	}
+}
+
+// WithInclude restricts scanning to templates matching any of the doublestar
+// patterns, relative to the scanned directory.
+func WithInclude(patterns ...string) ExtractorOption {
+	return func(e *Extractor) {
+		e.include = patterns
+	}
+}
+
+// WithExclude skips files and directories matching any of the doublestar
+// patterns, relative to the scanned directory (e.g. "**/node_modules").
+func WithExclude(patterns ...string) ExtractorOption {
+	return func(e *Extractor) {
+		e.exclude = patterns
+	}
+}
+
+// WithGitignore sets whether paths ignored by .gitignore files are skipped,
+// which is the default. The .gitignore files of the directories above the
+// scanned one apply too, up to the root of its git repository.
+func WithGitignore(enabled bool) ExtractorOption {
+	return func(e *Extractor) {
+		e.noGitignore = !enabled
+	}
+}
+
+// WithHidden sets whether hidden files and directories are scanned. They are
+// skipped by default.
+func WithHidden(enabled bool) ExtractorOption {
+	return func(e *Extractor) {
+		e.hidden = enabled
+	}
+}
+
+// WithWorkers sets how many templates are scanned concurrently. Zero or less
+// uses GOMAXPROCS, which is the default.
+func WithWorkers(n int) ExtractorOption {
+	return func(e *Extractor) {
+		e.workers = n
+	}
+}
+
+// WithCache enables the incremental extraction cache stored at path. Templates
+// whose content hash is unchanged are not scanned again; the whole cache is
+// discarded when delimiters or translation functions change.
+func WithCache(path string) ExtractorOption {
+	return func(e *Extractor) {
+		e.cache = path
+	}
+}
+
+// WithFS scans templates from fsys, e.g. an embed.FS or fstest.MapFS, instead
+// of the OS filesystem. The scanned directory is then a slash separated path
+// in fsys.
+func WithFS(fsys fs.FS) ExtractorOption {
+	return func(e *Extractor) {
+		e.fsys = fsys
+	}
+}
+
+// WithSink sets where the messages JSON and the Go file are written,
+// DirSink by default.
+func WithSink(sink Sink) ExtractorOption {
+	return func(e *Extractor) {
+		e.sink = sink
+	}
+}
+
+// WithStrict makes Extract fail without writing any file when templates
+// produce warnings.
+func WithStrict(strict bool) ExtractorOption {
+	return func(e *Extractor) {
+		e.strict = strict
+	}
+}
+
+// WithFilePositions lists message positions as file names only, so edits
+// that move a call within a template do not change the output.
+func WithFilePositions(fileOnly bool) ExtractorOption {
+	return func(e *Extractor) {
+		e.filePositions = fileOnly
+	}
+}
+
+// WithSourceLanguage sets the language the messages are written in,
+// SourceLanguage by default. It is recorded in the messages JSON and used by
+// the printer of the synthetic Go file, so gotext takes it as the source
+// language.
+func WithSourceLanguage(tag language.Tag) ExtractorOption {
+	return func(e *Extractor) {
+		e.sourceLang = tag
+	}
+}
+
+// WithDomains maps templates to domains, each written to its own messages
+// JSON and Go file, e.g. {"admin": {"admin/**"}}. A template belongs to the
+// first domain in name order with a doublestar pattern matching its path
+// relative to the scanned directory, unless it declares its domain with a
+// {{/* i18n:domain name */}} comment.
+func WithDomains(domains map[string][]string) ExtractorOption {
+	return func(e *Extractor) {
+		e.domains = domains
+	}
+}
+
+// WithModules also scans the templates of the Go modules required by the
+// main module, e.g. a module of shared UI components, so the application can
+// translate or override their messages. Their positions start with the
+// module path, as in github.com/acme/ui/button.html:1:7, which include,
+// exclude and domain patterns match too. Templates of dependencies are
+// skipped otherwise, including vendored ones.
+func WithModules(modules ...string) ExtractorOption {
+	return func(e *Extractor) {
+		e.modules = modules
+	}
+}
+
+// WithLog sets where progress and warnings are reported, os.Stdout by
+// default. Use io.Discard to silence the extractor.
+func WithLog(w io.Writer) ExtractorOption {
+	return func(e *Extractor) {
+		e.log = w
+	}
+}
+
+// LoadExtractorConfig reads a YAML config file. Relative paths in it are
+// resolved against the directory of the file.
+func LoadExtractorConfig(path string) (ExtractorConfig, error) {
+	var cfg ExtractorConfig
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+
+	base := filepath.Dir(path)
+	for _, p := range []*string{&cfg.Dir, &cfg.Out, &cfg.GoFile, &cfg.Cache} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(base, *p)
+		}
+	}
+
+	return cfg, nil
+}
+
+// FindConfig looks for ConfigFile in dir and its parents, stopping at the
+// module or repository root (a directory with go.mod or .git).
+func FindConfig(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		path := filepath.Join(dir, ConfigFile)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+
+		if exists(filepath.Join(dir, "go.mod")) || exists(filepath.Join(dir, ".git")) {
+			return "", false
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// UnmarshalYAML accepts either a spec string understood by ParseFunc or a
+// mapping with name, arg and html keys.
+func (f *Func) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		parsed, err := ParseFunc(value.Value)
+		if err != nil {
+			return err
+		}
+		*f = parsed
+		return nil
+	}
+
+	aux := struct {
+		Name string `yaml:"name"`
+		Arg  *int   `yaml:"arg"`
+		HTML bool   `yaml:"html"`
+	}{}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if aux.Name == "" {
+		return errors.New("invalid function: empty name")
+	}
+
+	*f = Func{Name: aux.Name, Arg: 1, HTML: aux.HTML}
+	if aux.Arg != nil {
+		f.Arg = *aux.Arg
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
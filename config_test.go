@@ -0,0 +1,122 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestLoadExtractorConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ConfigFile)
+	require.NoError(t, os.WriteFile(path, []byte(`
+dir: themes
+out: locales/messages.json
+pkg: locales
+gofile: /abs/gotext_stub.go
+ext: [.html, .gohtml]
+left_delim: "[["
+right_delim: "]]"
+funcs:
+  - tr
+  - trp:0
+  - name: trh
+    html: true
+  - name: trn
+    arg: 2
+include: ["**/*.html"]
+exclude: ["**/node_modules"]
+no_gitignore: true
+hidden: true
+workers: 4
+cache: .i18n-cache
+source_language: it
+domains:
+  admin: ["admin/**"]
+modules: [github.com/acme/ui]
+`), 0644))
+
+	cfg, err := LoadExtractorConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, ExtractorConfig{
+		Dir:        filepath.Join(dir, "themes"),
+		Out:        filepath.Join(dir, "locales/messages.json"),
+		Pkg:        "locales",
+		GoFile:     "/abs/gotext_stub.go",
+		Exts:       []string{".html", ".gohtml"},
+		LeftDelim:  "[[",
+		RightDelim: "]]",
+		Funcs: []Func{
+			{Name: "tr", Arg: 1},
+			{Name: "trp", Arg: 0},
+			{Name: "trh", Arg: 1, HTML: true},
+			{Name: "trn", Arg: 2},
+		},
+		Include:     []string{"**/*.html"},
+		Exclude:     []string{"**/node_modules"},
+		NoGitignore: true,
+		Hidden:      true,
+		Workers:     4,
+		Cache:       filepath.Join(dir, ".i18n-cache"),
+
+		SourceLanguage: language.Italian,
+
+		Domains: map[string][]string{"admin": {"admin/**"}},
+		Modules: []string{"github.com/acme/ui"},
+	}, cfg)
+}
+
+func TestLoadExtractorConfigError(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadExtractorConfig(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+
+	tests := map[string]string{
+		"invalid yaml":      "dir: [",
+		"invalid func spec": "funcs: [\"tr:x\"]",
+		"func without name": "funcs: [{arg: 1}]",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "broken.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+			_, err := LoadExtractorConfig(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "app", "locales")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example\n"), 0644))
+
+	t.Run("Stops at module root", func(t *testing.T) {
+		_, ok := FindConfig(nested)
+		assert.False(t, ok)
+	})
+
+	t.Run("Found at module root", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, ConfigFile), []byte("dir: themes\n"), 0644))
+
+		path, ok := FindConfig(nested)
+		assert.True(t, ok)
+		assert.Equal(t, filepath.Join(root, ConfigFile), path)
+	})
+
+	t.Run("Nearest wins", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(nested, ConfigFile), []byte("dir: themes\n"), 0644))
+
+		path, ok := FindConfig(nested)
+		assert.True(t, ok)
+		assert.Equal(t, filepath.Join(nested, ConfigFile), path)
+	})
+}
@@ -0,0 +1,240 @@
+package i18n
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// csvColumns are the columns of a CSV export before the language columns.
+var csvColumns = []string{"id", "comment", "source", "translatorComment", "positions"}
+
+// csvEscaped are the first characters of the cells prefixed with a ' by
+// WriteCSV: those making spreadsheets evaluate a cell as a formula, and the
+// prefix itself.
+const csvEscaped = "=+-@\t\r'"
+
+// reCaseLine matches a "case: text" line of a plural message in a CSV cell.
+var reCaseLine = regexp.MustCompile(`^(zero|one|two|few|many|other|[=<]\d+): ?(.*)$`)
+
+// WriteCSV writes the messages of files as a spreadsheet for reviewers: one
+// row per message key and one column per language. The columns are:
+//
+//   - id: the key of the message
+//   - comment: the comment of the developer
+//   - source: the source text
+//   - translatorComment: the comment of the translators
+//   - positions: where the message is used
+//   - one column per language with its translation
+//
+// Placeholders are shown as format verbs and plural messages as one
+// "case: text" line per case. Cells starting with =, +, - or @, which
+// spreadsheets would evaluate as formulas, are prefixed with a ', which
+// ReadCSV removes.
+func WriteCSV(w io.Writer, files ...*TranslationFile) error {
+	type row struct {
+		message      *Translation
+		source       Text
+		comment      string
+		translations map[string]Text
+	}
+
+	var (
+		rows  []*row
+		byKey = make(map[string]*row)
+		langs = make(map[string]struct{})
+	)
+
+	for _, f := range files {
+		tag, err := language.Parse(f.Language)
+		if err != nil {
+			return fmt.Errorf("%q: %w", f.Language, err)
+		}
+		lang := tag.String()
+		langs[lang] = struct{}{}
+
+		for _, m := range f.Messages {
+			key := translationKey(m)
+			r := byKey[key]
+			if r == nil {
+				source, err := formatSource(m)
+				if err != nil {
+					return fmt.Errorf("%s: %q: %w", lang, m.ID, err)
+				}
+				r = &row{message: m, source: source, translations: make(map[string]Text)}
+				byKey[key] = r
+				rows = append(rows, r)
+			}
+
+			text, _, _, err := formatText(m)
+			if err != nil {
+				return fmt.Errorf("%s: %q: %w", lang, m.ID, err)
+			}
+			r.translations[lang] = text
+			if r.comment == "" {
+				r.comment = m.TranslatorComment
+			}
+		}
+	}
+
+	columns := slices.Sorted(maps.Keys(langs))
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(slices.Clone(csvColumns), columns...)); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := []string{translationKey(r.message), r.message.Comment, csvText(r.source), r.comment, r.message.Position}
+		for _, lang := range columns {
+			record = append(record, csvText(r.translations[lang]))
+		}
+		for i, cell := range record {
+			record[i] = csvCell(cell)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads a spreadsheet written by WriteCSV and returns one translation
+// file per language column, to be applied with TranslationFile.Import.
+// Columns may be reordered; id and at least one language are required.
+func ReadCSV(r io.Reader) ([]*TranslationFile, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv: missing header")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+
+	index := make(map[string]int)
+	var files []*TranslationFile
+	langColumns := make(map[int]*TranslationFile)
+
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if slices.Contains(csvColumns, name) {
+			index[name] = i
+			continue
+		}
+
+		tag, err := language.Parse(name)
+		if err != nil {
+			return nil, fmt.Errorf("csv: column %q is neither %s nor a language", name, strings.Join(csvColumns, ", "))
+		}
+		f := &TranslationFile{Language: tag.String()}
+		files = append(files, f)
+		langColumns[i] = f
+	}
+
+	if _, ok := index["id"]; !ok {
+		return nil, errors.New("csv: missing id column")
+	}
+	if len(files) == 0 {
+		return nil, errors.New("csv: missing language columns")
+	}
+
+	cell := func(record []string, name string) string {
+		if i, ok := index[name]; ok && i < len(record) {
+			return parseCSVCell(record[i])
+		}
+		return ""
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+
+		key := cell(record, "id")
+		if key == "" {
+			continue
+		}
+
+		for i, f := range langColumns {
+			if i >= len(record) {
+				continue
+			}
+			f.Messages = append(f.Messages, &Translation{
+				ID:                key,
+				Key:               key,
+				Message:           parseCSVText(cell(record, "source")),
+				Translation:       parseCSVText(parseCSVCell(record[i])),
+				Comment:           cell(record, "comment"),
+				TranslatorComment: cell(record, "translatorComment"),
+				Position:          cell(record, "positions"),
+			})
+		}
+	}
+	return files, nil
+}
+
+// csvCell returns s as a CSV cell, prefixed with a ' if it starts with one
+// of csvEscaped.
+func csvCell(s string) string {
+	if s != "" && strings.IndexByte(csvEscaped, s[0]) >= 0 {
+		return "'" + s
+	}
+	return s
+}
+
+// parseCSVCell is the inverse of csvCell.
+func parseCSVCell(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.IndexByte(csvEscaped, s[1]) >= 0 {
+		return s[1:]
+	}
+	return s
+}
+
+// csvText returns a text as a CSV cell: plural cases as "case: text" lines.
+func csvText(t Text) string {
+	if t.Cases == nil {
+		return t.Msg
+	}
+
+	lines := make([]string, 0, len(t.Cases))
+	for _, c := range pluralCases(t.Cases) {
+		lines = append(lines, c+": "+t.Cases[c])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseCSVText is the inverse of csvText: a cell of "case: text" lines with
+// an "other" case is a plural text.
+func parseCSVText(s string) Text {
+	if !strings.Contains(s, "\n") && !strings.HasPrefix(s, "other:") {
+		return Text{Msg: s}
+	}
+
+	cases := make(map[string]string)
+	for line := range strings.SplitSeq(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		m := reCaseLine.FindStringSubmatch(line)
+		if m == nil {
+			return Text{Msg: s}
+		}
+		cases[m[1]] = m[2]
+	}
+	if !isPlural(slices.Collect(maps.Keys(cases))) {
+		return Text{Msg: s}
+	}
+	return Text{Cases: cases}
+}
@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	placeholders := json.RawMessage(`[{"id": "Count", "string": "%[1]d", "argNum": 1}]`)
+	files := []*TranslationFile{
+		{Language: "fr", Messages: []*Translation{
+			{ID: "checkout.pay", Message: Text{Msg: "Pay now"}, Translation: Text{Msg: "Payer"}, Comment: "checkout button", Position: "shop.html:3:5"},
+		}},
+		{Language: "de-DE", Messages: []*Translation{
+			{ID: "checkout.pay", Message: Text{Msg: "Pay now"}, Translation: Text{Msg: "Bezahlen"}, TranslatorComment: "formal"},
+			{ID: "{Count} items", Key: "%d items", Message: Text{Msg: "{Count} items"}, Translation: Text{Arg: "Count", Cases: map[string]string{"one": "ein Artikel", "other": "{Count} Artikel"}}, Placeholders: placeholders},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, files...))
+	assert.Equal(t, `id,comment,source,translatorComment,positions,de-DE,fr
+checkout.pay,checkout button,Pay now,formal,shop.html:3:5,Bezahlen,Payer
+%d items,,%[1]d items,,,"one: ein Artikel
+other: %[1]d Artikel",
+`, buf.String())
+
+	assert.Error(t, WriteCSV(&buf, &TranslationFile{Language: "not a tag"}))
+
+	t.Run("Round trip", func(t *testing.T) {
+		read, err := ReadCSV(strings.NewReader(buf.String()))
+		require.NoError(t, err)
+		require.Len(t, read, 2)
+
+		assert.Equal(t, "de-DE", read[0].Language)
+		assert.Equal(t, []*Translation{
+			{ID: "checkout.pay", Key: "checkout.pay", Message: Text{Msg: "Pay now"}, Translation: Text{Msg: "Bezahlen"}, Comment: "checkout button", TranslatorComment: "formal", Position: "shop.html:3:5"},
+			{ID: "%d items", Key: "%d items", Message: Text{Msg: "%[1]d items"}, Translation: Text{Cases: map[string]string{"one": "ein Artikel", "other": "%[1]d Artikel"}}},
+		}, read[0].Messages)
+
+		assert.Equal(t, "fr", read[1].Language)
+		assert.Equal(t, Text{}, read[1].Messages[1].Translation)
+
+		res, err := files[1].Import(read[0])
+		require.NoError(t, err)
+		assert.Empty(t, res.Conflicts)
+		assert.Zero(t, res.Changed)
+	})
+}
+
+func TestCSVFormulas(t *testing.T) {
+	files := []*TranslationFile{{Language: "de", Messages: []*Translation{
+		{ID: "%d items", Message: Text{Msg: "%d items"}, Translation: Text{Cases: map[string]string{"=0": "keine Artikel", "other": "%d Artikel"}}},
+		{ID: "discount", Message: Text{Msg: "-10% today"}, Translation: Text{Msg: "=HYPERLINK(\"http://evil\")"}, Comment: "@team"},
+		{ID: "quote", Message: Text{Msg: "'quoted'"}, Translation: Text{Msg: "+49"}},
+	}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, files...))
+	assert.Equal(t, `id,comment,source,translatorComment,positions,de
+%d items,,%d items,,,"'=0: keine Artikel
+other: %d Artikel"
+discount,'@team,'-10% today,,,"'=HYPERLINK(""http://evil"")"
+quote,,''quoted',,,'+49
+`, buf.String())
+
+	read, err := ReadCSV(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	require.Len(t, read, 1)
+	assert.Equal(t, []*Translation{
+		{ID: "%d items", Key: "%d items", Message: Text{Msg: "%d items"}, Translation: Text{Cases: map[string]string{"=0": "keine Artikel", "other": "%d Artikel"}}},
+		{ID: "discount", Key: "discount", Message: Text{Msg: "-10% today"}, Translation: Text{Msg: "=HYPERLINK(\"http://evil\")"}, Comment: "@team"},
+		{ID: "quote", Key: "quote", Message: Text{Msg: "'quoted'"}, Translation: Text{Msg: "+49"}},
+	}, read[0].Messages)
+
+	res, err := files[0].Import(read[0])
+	require.NoError(t, err)
+	assert.Empty(t, res.Conflicts)
+	assert.Zero(t, res.Changed)
+}
+
+func TestReadCSV(t *testing.T) {
+	files, err := ReadCSV(strings.NewReader("de,id\nHallo,Hello\n,\nTschüss\n"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, []*Translation{{ID: "Hello", Key: "Hello", Translation: Text{Msg: "Hallo"}}}, files[0].Messages)
+
+	for input, want := range map[string]string{
+		"":                  "missing header",
+		"id,source\n":       "missing language columns",
+		"de,fr\n":           "missing id column",
+		"id,notes\n":        `column "notes" is neither`,
+		"id,de\n\"broken\n": "csv:",
+	} {
+		_, err := ReadCSV(strings.NewReader(input))
+		assert.ErrorContains(t, err, want, input)
+	}
+}
+
+func TestParseCSVText(t *testing.T) {
+	assert.Equal(t, Text{Msg: "Hello"}, parseCSVText("Hello"))
+	assert.Equal(t, Text{Msg: "line one\nline two"}, parseCSVText("line one\nline two"))
+	assert.Equal(t, Text{Msg: "one: a\ntwo: b"}, parseCSVText("one: a\ntwo: b"))
+	assert.Equal(t, Text{Cases: map[string]string{"other": "%d"}}, parseCSVText("other: %d"))
+	assert.Equal(t, Text{Cases: map[string]string{"=0": "none", "other": "%d"}}, parseCSVText("=0: none\r\nother: %d"))
+}
@@ -0,0 +1,22 @@
+package i18n
+
+import (
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// unifiedDiff returns the unified diff from the current content of name to
+// the extracted content, or "" if they are equal.
+func unifiedDiff(name string, current, extracted []byte) string {
+	if string(current) == string(extracted) {
+		return ""
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		B:        difflib.SplitLines(string(extracted)),
+		FromFile: name,
+		ToFile:   name + " (extracted)",
+		Context:  3,
+	})
+	return diff
+}
@@ -0,0 +1,191 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// DomainLoader returns the catalog of a domain. It is called once, the first
+// time the domain is used. A catalog returned together with an error, e.g.
+// when some messages could not be read, is still used.
+type DomainLoader func() (catalog.Catalog, error)
+
+// DomainFS returns a DomainLoader reading every translation file in dir of
+// fsys, e.g. locales/admin of an embed.FS, like LoadTranslations.
+func DomainFS(fsys fs.FS, dir string) DomainLoader {
+	return func() (catalog.Catalog, error) {
+		b := newKeyedCatalog()
+		return b, loadTranslations(fsys, dir, b.Set)
+	}
+}
+
+// SetDomain registers the catalog of the domain name, such as the messages
+// of an admin theme, loaded by load the first time it is used. A nil load
+// removes the domain.
+func SetDomain(name string, load DomainLoader) {
+	std.SetDomain(name, load)
+}
+
+// LoadDomain loads the catalog of the domain name now, e.g. to report a
+// broken catalog at startup, and returns the error of its loader.
+func LoadDomain(name string) error {
+	return std.LoadDomain(name)
+}
+
+// TDomain is like T, translating key with the catalog of domain first.
+// Messages the domain lacks are translated like T translates them.
+func TDomain(domain string, tag language.Tag, key message.Reference, a ...any) string {
+	return std.TDomain(domain, tag, key, a...)
+}
+
+// ThtmlDomain is like Thtml, translating key with the catalog of domain
+// first.
+func ThtmlDomain(domain string, tag language.Tag, key message.Reference, a ...any) template.HTML {
+	return std.ThtmlDomain(domain, tag, key, a...)
+}
+
+// NewFuncMapDomain is like NewFuncMap, translating with the catalog of
+// domain first. It is meant for the templates mapped to domain when
+// extracting.
+func NewFuncMapDomain(domain string, funcs ...Func) template.FuncMap {
+	return std.FuncMapDomain(domain, funcs...)
+}
+
+// TDomainContext is like TDomain, preferring the overrides of the tenant of
+// ctx to the catalog of domain.
+func TDomainContext(ctx context.Context, domain string, tag language.Tag, key message.Reference, a ...any) string {
+	return std.TDomainContext(ctx, domain, tag, key, a...)
+}
+
+// ThtmlDomainContext is like ThtmlDomain, preferring the overrides of the
+// tenant of ctx to the catalog of domain.
+func ThtmlDomainContext(ctx context.Context, domain string, tag language.Tag, key message.Reference, a ...any) template.HTML {
+	return std.ThtmlDomainContext(ctx, domain, tag, key, a...)
+}
+
+// NewFuncMapDomainContext is like NewFuncMapDomain, preferring the overrides
+// of the tenant of ctx to the catalog of domain.
+func NewFuncMapDomainContext(ctx context.Context, domain string, funcs ...Func) template.FuncMap {
+	return std.FuncMapDomainContext(ctx, domain, funcs...)
+}
+
+// SetDomain registers the catalog of the domain name, loaded by load the
+// first time it is used. Replacing a domain drops its loaded catalog. A nil
+// load removes the domain.
+func (tr *Translator) SetDomain(name string, load DomainLoader) {
+	if load == nil {
+		tr.domains.Delete(name)
+		return
+	}
+	tr.domains.Store(name, &domain{load: load})
+}
+
+// LoadDomain loads the catalog of the domain name now and returns the error
+// of its loader.
+func (tr *Translator) LoadDomain(name string) error {
+	d, ok := tr.domain(name)
+	if !ok {
+		return fmt.Errorf("unknown domain %q", name)
+	}
+	_, err := d.get()
+	return err
+}
+
+// TDomain is like T, translating key with the catalog of domain first.
+func (tr *Translator) TDomain(domain string, tag language.Tag, key message.Reference, a ...any) string {
+	return view{tr: tr, domain: domain}.t(tag, key, a...)
+}
+
+// ThtmlDomain is like Thtml, translating key with the catalog of domain
+// first.
+func (tr *Translator) ThtmlDomain(domain string, tag language.Tag, key message.Reference, a ...any) template.HTML {
+	return view{tr: tr, domain: domain}.thtml(tag, key, a...)
+}
+
+// FuncMapDomain is like FuncMap, translating with the catalog of domain
+// first.
+func (tr *Translator) FuncMapDomain(domain string, funcs ...Func) template.FuncMap {
+	return view{tr: tr, domain: domain}.funcMap(funcs)
+}
+
+// TDomainContext is like TDomain, preferring the overrides of the tenant of
+// ctx.
+func (tr *Translator) TDomainContext(ctx context.Context, domain string, tag language.Tag, key message.Reference, a ...any) string {
+	return tr.domainView(ctx, domain).t(tag, key, a...)
+}
+
+// ThtmlDomainContext is like ThtmlDomain, preferring the overrides of the
+// tenant of ctx.
+func (tr *Translator) ThtmlDomainContext(ctx context.Context, domain string, tag language.Tag, key message.Reference, a ...any) template.HTML {
+	return tr.domainView(ctx, domain).thtml(tag, key, a...)
+}
+
+// FuncMapDomainContext is like FuncMapDomain, preferring the overrides of
+// the tenant of ctx.
+func (tr *Translator) FuncMapDomainContext(ctx context.Context, domain string, funcs ...Func) template.FuncMap {
+	return tr.domainView(ctx, domain).funcMap(funcs)
+}
+
+func (tr *Translator) domainView(ctx context.Context, domain string) view {
+	v := tr.view(ctx)
+	v.domain = domain
+	return v
+}
+
+func (tr *Translator) domain(name string) (*domain, bool) {
+	d, ok := tr.domains.Load(name)
+	if !ok {
+		return nil, false
+	}
+	return d.(*domain), true
+}
+
+// domainPrinter returns the printer of the domain name if it has key in tag
+// or, with supported languages set, in the language tag is printed in.
+// Unknown domains and domains that failed to load have no messages.
+func (tr *Translator) domainPrinter(name string, tag language.Tag, key string) (catalogPrinter, bool) {
+	d, ok := tr.domain(name)
+	if !ok {
+		return catalogPrinter{}, false
+	}
+
+	p, _ := d.get()
+	if p == nil {
+		return catalogPrinter{}, false
+	}
+	if printer, ok := p.catalogPrinter(tag, key); ok {
+		return printer, true
+	}
+	if set := supported.Load(); set != nil {
+		if resolved := set.resolve(tag); resolved != tag {
+			return p.catalogPrinter(resolved, key)
+		}
+	}
+	return catalogPrinter{}, false
+}
+
+// domain is the lazily loaded catalog of a domain.
+type domain struct {
+	load     DomainLoader
+	once     sync.Once
+	provider *catalogProvider
+	err      error
+}
+
+func (d *domain) get() (*catalogProvider, error) {
+	d.once.Do(func() {
+		cat, err := d.load()
+		if cat != nil {
+			d.provider = &catalogProvider{cat: cat}
+		}
+		d.err = err
+	})
+	return d.provider, d.err
+}
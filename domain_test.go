@@ -0,0 +1,140 @@
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestDomain(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/admin/de.yaml": {Data: []byte("Save: Speichern\nHello %s: Hallo %s\n")},
+		"locales/shop/de.yaml":  {Data: []byte("Save: In den Warenkorb\n")},
+	}
+
+	tr := NewTranslator(nil)
+
+	var loads atomic.Int32
+	admin := DomainFS(fsys, "locales/admin")
+	tr.SetDomain("admin", func() (catalog.Catalog, error) {
+		loads.Add(1)
+		return admin()
+	})
+	tr.SetDomain("shop", DomainFS(fsys, "locales/shop"))
+
+	t.Run("Domains are loaded lazily", func(t *testing.T) {
+		assert.Zero(t, loads.Load())
+		assert.Equal(t, "Speichern", tr.TDomain("admin", language.German, "Save"))
+		assert.Equal(t, "Hallo <b>", tr.TDomain("admin", language.MustParse("de-AT"), "Hello %s", "<b>"))
+		assert.Equal(t, template.HTML("Hallo &lt;b&gt;"), tr.ThtmlDomain("admin", language.German, "Hello %s", "<b>"))
+		assert.Equal(t, int32(1), loads.Load())
+	})
+
+	t.Run("Domains do not collide", func(t *testing.T) {
+		assert.Equal(t, "In den Warenkorb", tr.TDomain("shop", language.German, "Save"))
+		assert.Equal(t, "Save", tr.T(language.German, "Save"))
+	})
+
+	t.Run("Missing messages are translated like T", func(t *testing.T) {
+		assert.Equal(t, "Hello Bob", tr.TDomain("shop", language.German, "Hello %s", "Bob"))
+		assert.Equal(t, "Pay now", tr.TDomain("shop", language.German, Key("checkout.pay", "Pay now")))
+		assert.Equal(t, "Save", tr.TDomain("admin", language.French, "Save"))
+		assert.Equal(t, "Save", tr.TDomain("admin", SourceLanguage(), "Save"))
+		assert.Equal(t, "Save", tr.TDomain("blog", language.German, "Save"))
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		tmpl := template.Must(template.New("admin").Funcs(tr.FuncMapDomain("admin", DefaultFuncs...)).Parse(`{{ T "de" "Save" }}`))
+
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.Execute(&buf, nil))
+		assert.Equal(t, "Speichern", buf.String())
+	})
+
+	t.Run("LoadDomain", func(t *testing.T) {
+		assert.NoError(t, tr.LoadDomain("shop"))
+		assert.ErrorContains(t, tr.LoadDomain("blog"), `unknown domain "blog"`)
+
+		tr.SetDomain("broken", func() (catalog.Catalog, error) { return nil, errors.New("boom") })
+		assert.EqualError(t, tr.LoadDomain("broken"), "boom")
+		assert.Equal(t, "Save", tr.TDomain("broken", language.German, "Save"))
+
+		assert.Error(t, tr.LoadDomain("missing"))
+		tr.SetDomain("missing", DomainFS(fsys, "locales/missing"))
+		assert.Error(t, tr.LoadDomain("missing"))
+	})
+
+	t.Run("Removing a domain", func(t *testing.T) {
+		tr.SetDomain("shop", nil)
+		assert.Equal(t, "Save", tr.TDomain("shop", language.German, "Save"))
+	})
+}
+
+func TestDomainFallback(t *testing.T) {
+	t.Cleanup(func() { SetLanguages() })
+
+	tr := NewTranslator(NewCatalogProvider(newTestCatalog(t, language.German, "Cancel", "Abbrechen")))
+	tr.SetDomain("admin", DomainFS(fstest.MapFS{
+		"de.yaml": {Data: []byte("Save: Speichern\n")},
+	}, "."))
+	tr.SetLibrary("ui", NewCatalogProvider(newTestCatalog(t, language.German, "Close", "Schließen")))
+	tr.SetTenantCatalog("acme", NewCatalogProvider(newTestCatalog(t, language.German, "Save", "Sichern")))
+
+	regional := language.MustParse("de-AT")
+
+	t.Run("Provider and libraries", func(t *testing.T) {
+		assert.Equal(t, "Speichern", tr.TDomain("admin", regional, "Save"))
+		assert.Equal(t, "Abbrechen", tr.TDomain("admin", regional, "Cancel"))
+		assert.Equal(t, "Schließen", tr.TDomain("admin", regional, "Close"))
+	})
+
+	t.Run("Tenants", func(t *testing.T) {
+		ctx := WithTenant(context.Background(), "acme")
+		assert.Equal(t, "Sichern", tr.TDomainContext(ctx, "admin", regional, "Save"))
+		assert.Equal(t, "Abbrechen", tr.TDomainContext(ctx, "admin", regional, "Cancel"))
+		assert.Equal(t, template.HTML("Sichern"), tr.ThtmlDomainContext(ctx, "admin", regional, "Save"))
+		assert.Equal(t, "Speichern", tr.TDomainContext(context.Background(), "admin", regional, "Save"))
+
+		tmpl := template.Must(template.New("admin").Funcs(tr.FuncMapDomainContext(ctx, "admin", DefaultFuncs...)).Parse(`{{ T "de" "Save" }} {{ T "de" "Close" }}`))
+
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.Execute(&buf, nil))
+		assert.Equal(t, "Sichern Schließen", buf.String())
+	})
+
+	t.Run("Supported languages", func(t *testing.T) {
+		SetLanguages(language.German, language.English)
+		assert.Equal(t, "Speichern", tr.TDomain("admin", language.Japanese, "Save"))
+		assert.Equal(t, "Save", tr.TDomain("admin", language.MustParse("en-GB"), "Save"))
+	})
+}
+
+func TestDomainPackageFunctions(t *testing.T) {
+	t.Cleanup(func() { SetDomain("test-admin", nil) })
+
+	SetDomain("test-admin", DomainFS(fstest.MapFS{
+		"de.yaml": {Data: []byte("Save: Speichern\n")},
+	}, "."))
+
+	require.NoError(t, LoadDomain("test-admin"))
+	assert.Equal(t, "Speichern", TDomain("test-admin", language.German, "Save"))
+	assert.Equal(t, template.HTML("Speichern"), ThtmlDomain("test-admin", language.German, "Save"))
+	assert.Equal(t, "Speichern", TDomainContext(context.Background(), "test-admin", language.German, "Save"))
+	assert.Equal(t, template.HTML("Speichern"), ThtmlDomainContext(context.Background(), "test-admin", language.German, "Save"))
+	assert.NotNil(t, NewFuncMapDomainContext(context.Background(), "test-admin", DefaultFuncs...)["T"])
+
+	tmpl := template.Must(template.New("admin").Funcs(NewFuncMapDomain("test-admin", DefaultFuncs...)).Parse(`{{ T "de" "Save" }}`))
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, nil))
+	assert.Equal(t, "Speichern", buf.String())
+}
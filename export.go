@@ -0,0 +1,264 @@
+package i18n
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// ExportTranslations returns the translations of files for key-value formats
+// such as YAML and TOML, one file per language sorted by language. Files of
+// the same language are merged, later messages replacing earlier ones with
+// the same key. Messages are keyed by their runtime key and gotext
+// placeholders are replaced by their format verbs.
+func ExportTranslations(files ...*TranslationFile) ([]*TranslationFile, error) {
+	byLang := make(map[string]*TranslationFile)
+	index := make(map[string]map[string]int)
+
+	for _, f := range files {
+		tag, err := language.Parse(f.Language)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", f.Language, err)
+		}
+
+		lang := tag.String()
+		if byLang[lang] == nil {
+			byLang[lang] = &TranslationFile{Language: lang}
+			index[lang] = make(map[string]int)
+		}
+		out := byLang[lang]
+
+		for _, m := range f.Messages {
+			text, _, _, err := formatText(m)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %q: %w", lang, m.ID, err)
+			}
+
+			key := translationKey(m)
+			exported := keyedTranslation(key, text, m.Comment)
+			if i, ok := index[lang][key]; ok {
+				out.Messages[i] = exported
+				continue
+			}
+			index[lang][key] = len(out.Messages)
+			out.Messages = append(out.Messages, exported)
+		}
+	}
+
+	exported := make([]*TranslationFile, 0, len(byLang))
+	for _, lang := range slices.Sorted(maps.Keys(byLang)) {
+		exported = append(exported, byLang[lang])
+	}
+	return exported, nil
+}
+
+// ImportResult reports what TranslationFile.Import changed.
+type ImportResult struct {
+	Changed   int      // messages whose translation or comment changed
+	Unknown   []string // keys of imported messages the file does not have
+	Conflicts []string // imported messages that were not applied, and why
+}
+
+// Import sets the translations of src, such as a file exported with
+// ExportTranslations or ReadCSV and edited, on the messages of f with the
+// same key. Format verbs are replaced by the gotext placeholders of f and
+// imported messages are no longer fuzzy. Translator comments of src replace
+// those of f.
+//
+// A message of src is a conflict and is not applied when its source text
+// differs from the current one, i.e. the source changed since the export, or
+// when src has different translations for the same key.
+func (f *TranslationFile) Import(src *TranslationFile) (*ImportResult, error) {
+	res := &ImportResult{}
+
+	byKey := make(map[string][]*Translation, len(f.Messages))
+	for _, m := range f.Messages {
+		byKey[translationKey(m)] = append(byKey[translationKey(m)], m)
+		if m.ID != translationKey(m) {
+			byKey[m.ID] = append(byKey[m.ID], m)
+		}
+	}
+
+	imported := make(map[string]Text, len(src.Messages))
+	duplicates := make(map[string]bool)
+	for _, s := range src.Messages {
+		key := translationKey(s)
+		if t, ok := imported[key]; ok && !textEqual(t, s.Translation) && !duplicates[key] {
+			duplicates[key] = true
+			res.Conflicts = append(res.Conflicts, fmt.Sprintf("%q: imported with different translations", key))
+		}
+		imported[key] = s.Translation
+	}
+
+	for _, s := range src.Messages {
+		key := translationKey(s)
+		if duplicates[key] {
+			continue
+		}
+
+		targets := byKey[key]
+		if len(targets) == 0 {
+			res.Unknown = append(res.Unknown, key)
+			continue
+		}
+
+		for _, m := range targets {
+			if err := importTranslation(m, s, res); err != nil {
+				return res, fmt.Errorf("%s: %q: %w", f.Language, m.ID, err)
+			}
+		}
+	}
+	return res, nil
+}
+
+// importTranslation sets the translation of s on m.
+func importTranslation(m, s *Translation, res *ImportResult) error {
+	if source := s.Message.String(); source != "" {
+		current, err := formatSource(m)
+		if err != nil {
+			return err
+		}
+		if current.String() != "" && source != current.String() && source != m.Message.String() {
+			res.Conflicts = append(res.Conflicts, fmt.Sprintf("%q: source changed to %q", translationKey(m), current.String()))
+			return nil
+		}
+	}
+
+	text, err := gotextText(m, s.Translation)
+	if err != nil {
+		return err
+	}
+
+	changed := m.Fuzzy || !textEqual(m.Translation, text)
+	m.Translation, m.Fuzzy = text, false
+	if s.TranslatorComment != "" && s.TranslatorComment != m.TranslatorComment {
+		m.TranslatorComment = s.TranslatorComment
+		changed = true
+	}
+
+	if changed {
+		res.Changed++
+	}
+	return nil
+}
+
+// gotextText returns t with the format verbs of the gotext placeholders of m
+// replaced by the placeholders. Plural texts select on the argument of the
+// current translation of m, or on its first placeholder.
+func gotextText(m *Translation, t Text) (Text, error) {
+	placeholders, err := m.placeholders()
+	if err != nil {
+		return Text{}, err
+	}
+
+	replacements := make([]string, 0, 2*len(placeholders))
+	for _, p := range placeholders {
+		replacements = append(replacements, p.String, "{"+p.ID+"}")
+	}
+
+	t = t.replace(strings.NewReplacer(replacements...).Replace)
+	if t.Cases != nil {
+		t.Arg = m.Translation.Arg
+		if t.Arg == "" && len(placeholders) > 0 {
+			t.Arg = placeholders[0].ID
+		}
+	}
+	return t, nil
+}
+
+// textEqual reports whether a and b are the same text.
+func textEqual(a, b Text) bool {
+	return a.Msg == b.Msg && a.Arg == b.Arg && maps.Equal(a.Cases, b.Cases)
+}
+
+// UpdateResult reports what TranslationFile.Update changed.
+type UpdateResult struct {
+	Added   int // extracted messages new to the file
+	Changed int // messages whose source text changed
+	Fuzzy   int // translated messages among Changed, marked fuzzy
+	Removed int // messages no longer extracted
+}
+
+// Update merges the extracted messages into f, as gotext update does:
+// messages new to f are added untranslated, messages whose source text
+// changed get the new one and, if translated, are marked fuzzy, and messages
+// no longer extracted are removed. Messages are kept in the order of
+// messages.
+func (f *TranslationFile) Update(messages []*Message) (*UpdateResult, error) {
+	res := &UpdateResult{}
+
+	byKey := make(map[string][]*Translation, len(f.Messages))
+	for _, m := range f.Messages {
+		byKey[translationKey(m)] = append(byKey[translationKey(m)], m)
+		if m.ID != translationKey(m) {
+			byKey[m.ID] = append(byKey[m.ID], m)
+		}
+	}
+
+	kept := make(map[*Translation]struct{}, len(f.Messages))
+	updated := make([]*Translation, 0, len(messages))
+	for _, msg := range messages {
+		source := msg.ID
+		if msg.Message != "" {
+			source = msg.Message
+		}
+
+		targets := byKey[msg.ID]
+		if len(targets) == 0 {
+			m := &Translation{ID: msg.ID, Message: Text{Msg: source}}
+			if len(msg.Positions) > 0 {
+				m.Position = msg.Positions[0]
+			}
+			updated = append(updated, m)
+			res.Added++
+			continue
+		}
+
+		for _, m := range targets {
+			if _, ok := kept[m]; ok {
+				continue
+			}
+			kept[m] = struct{}{}
+			updated = append(updated, m)
+
+			if err := updateSource(m, source, res); err != nil {
+				return res, fmt.Errorf("%s: %q: %w", f.Language, m.ID, err)
+			}
+		}
+	}
+
+	res.Removed = len(f.Messages) - len(kept)
+	f.Messages = updated
+	return res, nil
+}
+
+// updateSource sets the source text of m to source, unless it is the format
+// gotext extracted m from. A translation of the previous source text is
+// marked fuzzy. Plural source texts are kept, as
+// extracted messages have none.
+func updateSource(m *Translation, source string, res *UpdateResult) error {
+	current, err := formatSource(m)
+	if err != nil {
+		return err
+	}
+	if current.Cases != nil || current.Msg == source || m.Key == source {
+		return nil
+	}
+
+	text, err := gotextText(m, Text{Msg: source})
+	if err != nil {
+		return err
+	}
+
+	translated := slices.ContainsFunc(m.Translation.Texts(), func(s string) bool { return s != "" })
+	if current.Msg != "" && translated {
+		m.Fuzzy = true
+		res.Fuzzy++
+	}
+	m.Message = text
+	res.Changed++
+	return nil
+}
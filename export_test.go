@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTranslations(t *testing.T) {
+	placeholders := json.RawMessage(`[{"id": "Count", "string": "%[1]d", "argNum": 1}]`)
+	files := []*TranslationFile{
+		{Language: "de-DE", Messages: []*Translation{
+			{ID: "{Count} items", Key: "%d items", Translation: Text{Arg: "Count", Cases: map[string]string{"one": "ein Artikel", "other": "{Count} Artikel"}}, Placeholders: placeholders},
+			{ID: "Bye", Translation: Text{Msg: "Tschau"}, Comment: "farewell"},
+		}},
+		{Language: "fr", Messages: []*Translation{{ID: "Bye", Translation: Text{Msg: "Salut"}}}},
+		{Language: "de_DE", Messages: []*Translation{{ID: "Bye", Translation: Text{Msg: "Tschüss"}}}},
+	}
+
+	exported, err := ExportTranslations(files...)
+	require.NoError(t, err)
+	require.Len(t, exported, 2)
+
+	assert.Equal(t, "de-DE", exported[0].Language)
+	assert.Equal(t, []*Translation{
+		{ID: "%d items", Key: "%d items", Translation: Text{Arg: "Count", Cases: map[string]string{"one": "ein Artikel", "other": "%[1]d Artikel"}}},
+		{ID: "Bye", Key: "Bye", Translation: Text{Msg: "Tschüss"}},
+	}, exported[0].Messages)
+	assert.Equal(t, "fr", exported[1].Language)
+
+	_, err = ExportTranslations(&TranslationFile{Language: "not a tag"})
+	assert.Error(t, err)
+
+	t.Run("Import", func(t *testing.T) {
+		f := files[0]
+		f.Messages[0].Fuzzy = true
+		exported[0].Messages[0].Translation.Cases["other"] = "%[1]d Artikel insgesamt"
+		exported[0].Messages = append(exported[0].Messages, keyedTranslation("Unknown", Text{Msg: "?"}, ""))
+
+		res, err := f.Import(exported[0])
+		require.NoError(t, err)
+		assert.Equal(t, &ImportResult{Changed: 2, Unknown: []string{"Unknown"}}, res)
+
+		assert.Equal(t, Text{Arg: "Count", Cases: map[string]string{"one": "ein Artikel", "other": "{Count} Artikel insgesamt"}}, f.Messages[0].Translation)
+		assert.False(t, f.Messages[0].Fuzzy)
+		assert.Equal(t, Text{Msg: "Tschüss"}, f.Messages[1].Translation)
+		assert.Equal(t, "farewell", f.Messages[1].Comment)
+
+		res, err = f.Import(exported[0])
+		require.NoError(t, err)
+		assert.Zero(t, res.Changed)
+	})
+
+	t.Run("Conflicts", func(t *testing.T) {
+		f := &TranslationFile{Language: "de", Messages: []*Translation{
+			{ID: "Hello {name}", Message: Text{Msg: "Hello {name}"}, Translation: Text{Msg: "Hallo {name}"}},
+			{ID: "checkout.pay", Message: Text{Msg: "Pay now"}, Translation: Text{Msg: "Zahlen"}},
+		}}
+
+		res, err := f.Import(&TranslationFile{Language: "de", Messages: []*Translation{
+			{ID: "Hello {name}", Message: Text{Msg: "Hi {name}"}, Translation: Text{Msg: "Servus {name}"}},
+			{ID: "checkout.pay", Translation: Text{Msg: "Bezahlen"}},
+			{ID: "checkout.pay", Translation: Text{Msg: "Jetzt zahlen"}},
+			{ID: "checkout.pay", Translation: Text{Msg: "Bezahlen"}},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, &ImportResult{Conflicts: []string{
+			`"checkout.pay": imported with different translations`,
+			`"Hello {name}": source changed to "Hello {name}"`,
+		}}, res)
+		assert.Equal(t, "Hallo {name}", f.Messages[0].Translation.Msg)
+		assert.Equal(t, "Zahlen", f.Messages[1].Translation.Msg)
+	})
+}
+
+func TestTranslationFileUpdate(t *testing.T) {
+	placeholders := json.RawMessage(`[{"id": "Name", "string": "%[1]s", "argNum": 1}]`)
+	f := &TranslationFile{Language: "de", Messages: []*Translation{
+		{ID: "Obsolete", Message: Text{Msg: "Obsolete"}, Translation: Text{Msg: "Veraltet"}},
+		{ID: "Hello {Name}", Key: "Hello %s", Message: Text{Msg: "Hello {Name}"}, Translation: Text{Msg: "Hallo {Name}"}, Placeholders: placeholders},
+		{ID: "checkout.pay", Message: Text{Msg: "Pay now"}, Translation: Text{Msg: "Jetzt zahlen"}},
+		{ID: "checkout.back", Message: Text{Msg: "Back"}},
+		{ID: "checkout.title", Translation: Text{Msg: "Kasse"}},
+	}}
+
+	res, err := f.Update([]*Message{
+		{ID: "Hello %s", Positions: []string{"a.html:1:7"}},
+		{ID: "Save", Positions: []string{"a.html:2:7", "b.html:1:7"}},
+		{ID: "checkout.back", Message: "Go back"},
+		{ID: "checkout.pay", Message: "Pay"},
+		{ID: "checkout.title", Message: "Checkout"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &UpdateResult{Added: 1, Changed: 3, Fuzzy: 1, Removed: 1}, res)
+
+	assert.Equal(t, []*Translation{
+		{ID: "Hello {Name}", Key: "Hello %s", Message: Text{Msg: "Hello {Name}"}, Translation: Text{Msg: "Hallo {Name}"}, Placeholders: placeholders},
+		{ID: "Save", Message: Text{Msg: "Save"}, Position: "a.html:2:7"},
+		{ID: "checkout.back", Message: Text{Msg: "Go back"}},
+		{ID: "checkout.pay", Message: Text{Msg: "Pay"}, Translation: Text{Msg: "Jetzt zahlen"}, Fuzzy: true},
+		{ID: "checkout.title", Message: Text{Msg: "Checkout"}, Translation: Text{Msg: "Kasse"}},
+	}, f.Messages)
+
+	res, err = f.Update([]*Message{{ID: "Hello %s"}, {ID: "Save"}, {ID: "checkout.back", Message: "Go back"}, {ID: "checkout.pay", Message: "Pay"}, {ID: "checkout.title", Message: "Checkout"}})
+	require.NoError(t, err)
+	assert.Equal(t, &UpdateResult{}, res)
+}
@@ -2,16 +2,26 @@ package i18n
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"maps"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
 )
 
 const (
@@ -24,150 +34,667 @@ import (
 )
 
 //nolint:unused
-func _i18n_extract() {
-	p := message.NewPrinter(language.English)
+func %s() {
+	p := message.NewPrinter(%s)
 `
 	goFileMessage = "\t_ = p.Sprintf(%s)\n"
+	goFileKey     = "\t_ = p.Sprintf(message.Key(%s, %s))\n"
+	goFileHTML    = "\t// HTML markup is allowed in this message.\n"
 	goFileFooter  = `}
 `
 )
 
-var (
-	reI18n          = regexp.MustCompile(`{{\s*(i18n|T|t)\s+`)
-	reAllStringArgs = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
-	unescape        = strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\n`, "\n", `\t`, "\t")
-)
+// ErrOutOfDate is returned by Check when the outputs differ from the files on
+// disk.
+var ErrOutOfDate = errors.New("generated files are out of date")
+
+var unescape = strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\n`, "\n", `\t`, "\t")
+
+// reDomain matches the domain declaration of a template, a template comment
+// such as {{/* i18n:domain admin */}}.
+var reDomain = regexp.MustCompile(`/\*\s*i18n:domain\s+([\w-]+)\s*\*/`)
+
+// reDomainName matches valid domain names, which are part of file names.
+var reDomainName = regexp.MustCompile(`^[\w-]+$`)
 
 // Message holds extracted message metadata.
 type Message struct {
-	ID        string   `json:"id"`                  // original message (singular or formatted)
-	Positions []string `json:"positions,omitempty"` // file:line:col where found
+	ID           string   `json:"id"`                     // original message (singular or formatted) or stable key
+	Message      string   `json:"message,omitempty"`      // source text of a keyed message
+	Positions    []string `json:"positions,omitempty"`    // file:line:col where found
+	HTML         bool     `json:"html,omitempty"`         // translation may contain HTML markup (Thtml)
+	Placeholders []string `json:"placeholders,omitempty"` // named placeholders, e.g. "name" for {name}
+	Domain       string   `json:"-"`                      // domain of the templates using it, empty for the default one
 }
 
 // OutputJSON is the top-level JSON structure we write (simple map by ID).
 type OutputJSON struct {
+	Language string     `json:"language,omitempty"` // source language of the messages
+	Domain   string     `json:"domain,omitempty"`   // domain of the messages, empty for the default one
+	Domains  []string   `json:"domains,omitempty"`  // of the files written with the default one
 	Messages []*Message `json:"messages"`
 }
 
+// Warning is a problem found in a template that does not stop extraction.
+type Warning struct {
+	Position string `json:"position"` // file:line:col
+	Message  string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return w.Position + ": " + w.Message
+}
+
+// Result is the outcome of ExtractMessages.
+type Result struct {
+	Messages []*Message    // extracted messages sorted by domain and ID
+	Warnings []Warning     // warnings in walk order
+	Files    int           // number of templates found
+	Cached   int           // number of templates served from the cache
+	Workers  int           // number of scan workers
+	Duration time.Duration // time spent walking and scanning
+}
+
 type Extractor struct {
-	dir    string
-	out    string
-	pkg    string
-	goFile string
-	exts   map[string]struct{}
+	dir         string
+	out         string
+	pkg         string
+	goFile      string
+	exts        map[string]struct{}
+	left        string
+	right       string
+	funcs       []Func
+	include     []string
+	exclude     []string
+	noGitignore bool
+	hidden      bool
+	workers     int
+	cache       string
+	strict      bool
+	fsys        fs.FS
+	sink        Sink
+	log         io.Writer
+	sourceLang  language.Tag
+	domains     map[string][]string
+	modules     []string
+
+	filePositions bool
 }
 
-func NewExtractor(dir, out, pkg, goFile string, exts ...string) *Extractor {
-	em := make(map[string]struct{}, len(exts))
-	for _, e := range exts {
-		em[e] = struct{}{}
+// NewExtractor returns an Extractor configured by opts. Without options it
+// scans the current directory for DefaultExts templates using DefaultFuncs
+// and writes gotext_stub.go in package main, with messages written in
+// SourceLanguage.
+func NewExtractor(opts ...ExtractorOption) *Extractor {
+	e := &Extractor{
+		dir:        ".",
+		pkg:        "main",
+		goFile:     "gotext_stub.go",
+		left:       "{{",
+		right:      "}}",
+		funcs:      DefaultFuncs,
+		sink:       DirSink{},
+		log:        os.Stdout,
+		sourceLang: SourceLanguage(),
 	}
+	WithExts(DefaultExts...)(e)
 
-	return &Extractor{
-		dir:    dir,
-		out:    out,
-		pkg:    pkg,
-		goFile: goFile,
-		exts:   em,
+	for _, opt := range opts {
+		opt(e)
 	}
+
+	return e
 }
 
+// Extract scans the templates and writes the messages JSON and the synthetic
+// Go file. In strict mode nothing is written if there are warnings.
 func (e *Extractor) Extract() error {
-	messages, err := e.extract()
+	res, err := e.strictMessages()
+	if err != nil {
+		return err
+	}
+
+	groups, domains := e.outputGroups(res.Messages)
+	if err := e.checkGoFuncs(groups); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, d := range groups {
+		errs = append(errs,
+			e.saveMessages(d.name, d.messages, domains...),
+			e.saveGoFile(d.name, d.messages),
+		)
+	}
+	return errors.Join(errs...)
+}
+
+// Check scans the templates without the cache and compares the messages JSON
+// and the synthetic Go file with the files held by the sink, which must be a
+// ReadSink. The files of domains that no longer have messages must be empty.
+// A unified diff of every out of date file is written to the log and
+// ErrOutOfDate is returned. Nothing is written.
+func (e *Extractor) Check() error {
+	rs, ok := e.sink.(ReadSink)
+	if !ok {
+		return errors.New("check: sink cannot read files")
+	}
+
+	c := *e
+	c.cache = ""
+
+	res, err := c.strictMessages()
 	if err != nil {
-		return fmt.Errorf("extract error: %w", err)
+		return err
+	}
+
+	type output struct {
+		name string
+		data []byte
 	}
+	var outputs []output
+
+	groups, domains := e.outputGroups(res.Messages)
+	if err := e.checkGoFuncs(groups); err != nil {
+		return err
+	}
+	for _, d := range groups {
+		if e.out != "" {
+			raw, err := e.messagesJSON(d.name, d.messages, domains...)
+			if err != nil {
+				return err
+			}
+			outputs = append(outputs, output{domainFile(e.out, d.name), raw})
+		}
+
+		if e.goFile != "" {
+			goCode, err := e.buildSyntheticGo(d.name, d.messages)
+			if err != nil {
+				return fmt.Errorf("build go file: %w", err)
+			}
+			outputs = append(outputs, output{domainFile(e.goFile, d.name), goCode})
+		}
+	}
+
+	var stale []string
+	for _, o := range outputs {
+		current, err := rs.ReadFile(o.name)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("check: %w", err)
+		}
+
+		if diff := unifiedDiff(o.name, current, o.data); diff != "" {
+			e.logf("%s", diff)
+			stale = append(stale, o.name)
+		}
+	}
+
+	if len(stale) > 0 {
+		return fmt.Errorf("%w: %s", ErrOutOfDate, strings.Join(stale, ", "))
+	}
+	return nil
+}
 
-	return errors.Join(
-		e.saveMessages(messages),
-		e.saveGoFile(messages),
+// strictMessages runs ExtractMessages and fails in strict mode if there are
+// warnings.
+func (e *Extractor) strictMessages() (*Result, error) {
+	res, err := e.ExtractMessages()
+	if err != nil {
+		return nil, err
+	}
+
+	if e.strict && len(res.Warnings) > 0 {
+		return nil, fmt.Errorf("strict mode: %d extraction warnings", len(res.Warnings))
+	}
+	return res, nil
+}
+
+// ExtractMessages scans the templates and returns the messages found without
+// writing any output file. The cache, if set, is still updated.
+func (e *Extractor) ExtractMessages() (*Result, error) {
+	res, err := e.extract()
+	if err != nil {
+		return nil, fmt.Errorf("extract error: %w", err)
+	}
+
+	for _, w := range res.Warnings {
+		e.logf("warning: %s\n", w)
+	}
+	e.logf("Scanned %d templates (%d cached) in %s (%d workers)\n", res.Files, res.Cached, res.Duration.Round(time.Millisecond), res.Workers)
+
+	return res, nil
+}
+
+func (e *Extractor) extract() (*Result, error) {
+	started := time.Now()
+
+	for name := range e.domains {
+		if !reDomainName.MatchString(name) {
+			return nil, fmt.Errorf("invalid domain %q", name)
+		}
+	}
+
+	files, err := e.templates()
+	if err != nil {
+		return nil, fmt.Errorf("walk error: %w", err)
+	}
+
+	workers := e.workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = max(1, min(workers, len(files)))
+
+	var cache *extractCache
+	if e.cache != "" {
+		cache = loadCache(e.cache, e.cacheSettings())
+	}
+
+	results, cached, err := e.scanFiles(files, workers, cache)
+	if err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	if cache != nil {
+		if err := cache.save(e.cache); err != nil {
+			return nil, err
+		}
+	}
+
+	// merging in walk order keeps positions and warnings stable regardless
+	// of which worker finished first
+	var (
+		all      = make(map[string]map[string]*Message) // by domain and ID
+		warnings []Warning
 	)
+	for _, found := range results {
+		if all[found.domain] == nil {
+			all[found.domain] = make(map[string]*Message)
+		}
+		warnings = append(warnings, found.warnings...)
+		warnings = append(warnings, mergeMessages(all[found.domain], found.messages)...)
+	}
+
+	var messages []*Message
+	for domain, found := range all {
+		for _, msg := range found {
+			msg.Domain = domain
+			messages = append(messages, msg)
+		}
+	}
+
+	slices.SortFunc(messages, func(a, b *Message) int {
+		return cmp.Or(strings.Compare(a.Domain, b.Domain), strings.Compare(a.ID, b.ID))
+	})
+
+	for _, msg := range messages {
+		msg.Positions = sortPositions(msg.Positions, e.filePositions)
+	}
+
+	return &Result{
+		Messages: messages,
+		Warnings: warnings,
+		Files:    len(files),
+		Cached:   cached,
+		Workers:  workers,
+		Duration: time.Since(started),
+	}, nil
 }
 
-func (e *Extractor) extract() ([]*Message, error) {
-	all := make(map[string]*Message)
+// scanResult is what the scanner found in one template.
+type scanResult struct {
+	messages map[string]*Message
+	warnings []Warning
+	domain   string
+}
 
-	err := filepath.WalkDir(e.dir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+// templateFile is a template found by the walk.
+type templateFile struct {
+	fsys fs.FS  // filesystem of the template
+	path string // slash separated path in fsys
+	rel  string // slash separated path relative to the scanned directory, used in positions
+}
+
+// templates walks the scanned directory and the directories of the modules
+// to scan, and returns the templates to scan in lexical order, those of the
+// modules last.
+func (e *Extractor) templates() ([]templateFile, error) {
+	fsys, root := e.source()
+
+	var ignore *gitignore
+	if !e.noGitignore {
+		ignore = new(gitignore)
+		if err := ignore.loadParents(e.parentSource()); err != nil {
+			return nil, err
 		}
+	}
 
-		if d.IsDir() {
-			return nil
+	files, err := e.walk(fsys, root, "", ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, module := range e.modules {
+		dir, err := moduleDir(e.workDir(), module)
+		if err != nil {
+			return nil, err
 		}
 
-		if !e.isTemplate(path) {
-			return nil
+		found, err := e.walk(os.DirFS(dir), ".", module, nil)
+		if err != nil {
+			return nil, err
 		}
+		files = append(files, found...)
+	}
+
+	return files, nil
+}
 
-		b, err := os.ReadFile(path)
+// walk returns the templates in root of fsys in lexical order, with their
+// paths relative to root prefixed by prefix. Vendored modules, directories
+// named vendor holding a modules.txt, are skipped. Unless ignore is nil, the
+// .gitignore files found are added to it.
+func (e *Extractor) walk(fsys fs.FS, root, prefix string, ignore *gitignore) ([]templateFile, error) {
+	var files []templateFile
+
+	err := fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		rel, _ := filepath.Rel(e.dir, path)
-		extractFromContent(all, b, rel)
+		rel := relPath(root, name)
+		if rel != "." && d.IsDir() && d.Name() == "vendor" {
+			if _, err := fs.Stat(fsys, path.Join(name, "modules.txt")); err == nil {
+				return fs.SkipDir
+			}
+		}
+		if prefix != "" && rel != "." {
+			rel = prefix + "/" + rel
+		}
+
+		if skip, err := e.skip(rel, d, ignore); skip || err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if ignore != nil {
+				return ignore.load(fsys, name, rel)
+			}
+			return nil
+		}
+
+		if e.isTemplate(name) {
+			files = append(files, templateFile{fsys: fsys, path: name, rel: rel})
+		}
 
 		return nil
 	})
+
+	return files, err
+}
+
+// workDir returns the directory the go command resolves modules in.
+func (e *Extractor) workDir() string {
+	if e.fsys != nil {
+		return "."
+	}
+	return e.dir
+}
+
+// moduleDirs caches the directories of modules by working directory and
+// module path.
+var moduleDirs sync.Map
+
+// moduleDir returns the directory of the module required by the main module
+// of dir, as listed by "go list -m".
+func moduleDir(dir, module string) (string, error) {
+	key := dir + "\x00" + module
+	if v, ok := moduleDirs.Load(key); ok {
+		return v.(string), nil
+	}
+
+	cmd := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", module)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("walk error: %w", err)
+		return "", fmt.Errorf("module %s: %w: %s", module, err, strings.TrimSpace(stderr.String()))
 	}
 
-	messages := slices.Collect(maps.Values(all))
+	moduleDir := strings.TrimSpace(string(out))
+	if moduleDir == "" {
+		return "", fmt.Errorf("module %s: not downloaded, run go mod download", module)
+	}
 
-	slices.SortFunc(messages, func(a, b *Message) int {
-		return strings.Compare(a.ID, b.ID)
-	})
+	moduleDirs.Store(key, moduleDir)
+	return moduleDir, nil
+}
+
+// source returns the filesystem to scan and the directory to walk in it.
+// Without WithFS the scanned directory is opened with os.DirFS.
+func (e *Extractor) source() (fs.FS, string) {
+	if e.fsys != nil {
+		return e.fsys, path.Clean(filepath.ToSlash(e.dir))
+	}
+	return os.DirFS(e.dir), "."
+}
 
-	return messages, nil
+// parentSource is like source, returning a filesystem that also holds the
+// directories above the scanned one: without WithFS, the root of its volume.
+func (e *Extractor) parentSource() (fs.FS, string) {
+	if e.fsys != nil {
+		return e.source()
+	}
+
+	abs, err := filepath.Abs(e.dir)
+	if err != nil {
+		return e.source()
+	}
+	vol := filepath.VolumeName(abs)
+	root := strings.Trim(filepath.ToSlash(abs[len(vol):]), "/")
+	if root == "" {
+		root = "."
+	}
+	return os.DirFS(vol + string(filepath.Separator)), root
 }
 
-func (e *Extractor) saveMessages(messages []*Message) error {
+// relPath returns the slash separated name relative to root.
+func relPath(root, name string) string {
+	switch {
+	case root == ".":
+		return name
+	case name == root:
+		return "."
+	}
+	return strings.TrimPrefix(name, root+"/")
+}
+
+// scanFiles reads and scans files with a pool of workers. The result for
+// each file is stored at the index of the file. Files whose content is
+// unchanged since the cache was written are not scanned again.
+func (e *Extractor) scanFiles(files []templateFile, workers int, cache *extractCache) ([]scanResult, int, error) {
+	var (
+		wg      sync.WaitGroup
+		jobs    = make(chan int)
+		results = make([]scanResult, len(files))
+		hashes  = make([]string, len(files))
+		errs    = make([]error, len(files))
+		sc      = newScanner(e.left, e.right, e.funcs)
+	)
+
+	for range workers {
+		wg.Go(func() {
+			for i := range jobs {
+				b, err := fs.ReadFile(files[i].fsys, files[i].path)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+
+				domain := e.templateDomain(files[i].rel, b)
+
+				if cache != nil {
+					hash := contentHash(b)
+					if found, ok := cache.get(files[i].rel, hash); ok {
+						found.domain = domain
+						results[i] = found
+						continue
+					}
+					hashes[i] = hash
+				}
+
+				found := make(map[string]*Message)
+				results[i] = scanResult{messages: found, warnings: sc.scan(found, b, files[i].rel), domain: domain}
+			}
+		})
+	}
+
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, 0, err
+	}
+
+	if cache == nil {
+		return results, 0, nil
+	}
+
+	cached := len(files)
+	for i, hash := range hashes {
+		if hash != "" {
+			cache.put(files[i].rel, hash, results[i])
+			cached--
+		}
+	}
+	cache.prune(files)
+
+	return results, cached, nil
+}
+
+// saveMessages writes the messages JSON of domain. The one of the default
+// domain records the other domains written.
+func (e *Extractor) saveMessages(domain string, messages []*Message, domains ...string) error {
 	if e.out == "" {
 		return nil
 	}
 
-	outJSON := OutputJSON{Messages: messages}
-
-	raw, err := json.MarshalIndent(outJSON, "", "  ")
+	raw, err := e.messagesJSON(domain, messages, domains...)
 	if err != nil {
-		return fmt.Errorf("json marshal: %w", err)
+		return err
 	}
 
-	if err := os.WriteFile(e.out, raw, 0644); err != nil {
+	out := domainFile(e.out, domain)
+	written, err := e.writeFile(out, raw)
+	switch {
+	case err != nil:
 		return fmt.Errorf("write out: %w", err)
+	case written:
+		e.logf("Wrote %d messages → %s\n", len(messages), out)
+	default:
+		e.logf("%s is up to date\n", out)
 	}
 
-	fmt.Printf("Wrote %d messages → %s\n", len(messages), e.out)
-
 	return nil
 }
 
-func (e *Extractor) saveGoFile(messages []*Message) error {
-	goCode, err := e.buildSyntheticGo(messages)
+func (e *Extractor) saveGoFile(domain string, messages []*Message) error {
+	if e.goFile == "" {
+		return nil
+	}
+
+	goCode, err := e.buildSyntheticGo(domain, messages)
 	if err != nil {
 		return fmt.Errorf("build go file: %w", err)
 	}
 
-	if err := os.WriteFile(e.goFile, goCode, 0644); err != nil {
+	goFile := domainFile(e.goFile, domain)
+	written, err := e.writeFile(goFile, goCode)
+	switch {
+	case err != nil:
 		return fmt.Errorf("write go file: %w", err)
+	case written:
+		e.logf("Wrote synthetic Go file → %s (run 'gotext extract/update' on it)\n", goFile)
+	default:
+		e.logf("%s is up to date\n", goFile)
 	}
 
-	fmt.Printf("Wrote synthetic Go file → %s (run 'gotext extract/update' on it)\n", e.goFile)
-
 	return nil
 }
 
-func (e *Extractor) buildSyntheticGo(messages []*Message) ([]byte, error) {
+// writeFile writes data to name unless the sink can read it back and it
+// already holds the same content. It reports whether the file was written.
+func (e *Extractor) writeFile(name string, data []byte) (bool, error) {
+	if rs, ok := e.sink.(ReadSink); ok {
+		if old, err := rs.ReadFile(name); err == nil && bytes.Equal(old, data) {
+			return false, nil
+		}
+	}
+	return true, e.sink.WriteFile(name, data)
+}
+
+// ReadMessagesFile reads the messages JSON written by Extract.
+func ReadMessagesFile(path string) ([]*Message, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var out OutputJSON
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, msg := range out.Messages {
+		msg.Domain = out.Domain
+	}
+	return out.Messages, nil
+}
+
+// messagesJSON encodes the messages of domain as the output JSON, ending
+// with a newline. The one of the default domain lists the other domains.
+func (e *Extractor) messagesJSON(domain string, messages []*Message, domains ...string) ([]byte, error) {
+	output := OutputJSON{Language: e.sourceLang.String(), Domain: domain, Messages: messages}
+	if domain == "" {
+		output.Domains = domains
+	}
+
+	raw, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+	return append(raw, '\n'), nil
+}
+
+func (e *Extractor) logf(format string, a ...any) {
+	_, _ = fmt.Fprintf(e.log, format, a...)
+}
+
+func (e *Extractor) buildSyntheticGo(domain string, messages []*Message) ([]byte, error) {
 	var buf bytes.Buffer
 
-	if _, err := fmt.Fprintf(&buf, goFileHeader, sanitizePkgName(e.pkg)); err != nil {
+	if _, err := fmt.Fprintf(&buf, goFileHeader, sanitizePkgName(e.pkg), goFileFunc(domain), languageExpr(e.sourceLang)); err != nil {
 		return nil, err
 	}
 
 	for _, msg := range messages {
-		if _, err := fmt.Fprintf(&buf, goFileMessage, strconvQuote(msg.ID)); err != nil {
+		if msg.HTML {
+			if _, err := buf.WriteString(goFileHTML); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if msg.Message != "" {
+			_, err = fmt.Fprintf(&buf, goFileKey, strconvQuote(msg.ID), strconvQuote(msg.Message))
+		} else {
+			_, err = fmt.Fprintf(&buf, goFileMessage, strconvQuote(msg.ID))
+		}
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -179,37 +706,379 @@ func (e *Extractor) buildSyntheticGo(messages []*Message) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// goFileFunc returns the name of the function of the synthetic Go file of
+// domain, e.g. _i18n_extract_check_out for check-out.
+func goFileFunc(domain string) string {
+	if domain == "" {
+		return "_i18n_extract"
+	}
+	return "_i18n_extract_" + strings.ReplaceAll(domain, "-", "_")
+}
+
+// checkGoFuncs returns an error if the synthetic Go files of two domains,
+// such as check-out and check_out, declare the same function, so they would
+// not compile as one package.
+func (e *Extractor) checkGoFuncs(groups []domainGroup) error {
+	if e.goFile == "" {
+		return nil
+	}
+
+	seen := make(map[string]string)
+	for _, d := range groups {
+		fn := goFileFunc(d.name)
+		if other, ok := seen[fn]; ok {
+			return fmt.Errorf("domains %q and %q have the same Go identifier %s", other, d.name, fn)
+		}
+		seen[fn] = d.name
+	}
+	return nil
+}
+
+// templateDomain returns the domain of the template at rel: the one it
+// declares, or else the first domain in name order with a pattern matching
+// rel. Templates without a domain belong to the default one, "".
+func (e *Extractor) templateDomain(rel string, content []byte) string {
+	if m := reDomain.FindSubmatch(content); m != nil {
+		return string(m[1])
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(e.domains)) {
+		if matchAny(e.domains[name], rel) {
+			return name
+		}
+	}
+	return ""
+}
+
+// domainGroup is the messages of one domain.
+type domainGroup struct {
+	name     string
+	messages []*Message
+}
+
+// domainMessages groups messages sorted by domain. The default domain comes
+// first and is always there, even without messages.
+func domainMessages(messages []*Message) []domainGroup {
+	groups := []domainGroup{{}}
+	for _, msg := range messages {
+		if groups[len(groups)-1].name != msg.Domain {
+			groups = append(groups, domainGroup{name: msg.Domain})
+		}
+		groups[len(groups)-1].messages = append(groups[len(groups)-1].messages, msg)
+	}
+	return groups
+}
+
+// outputGroups returns the messages of every output file and the domains
+// with messages. The domains the messages JSON lists but that no longer
+// have messages get no messages, so their files are emptied rather than
+// left stale.
+func (e *Extractor) outputGroups(messages []*Message) ([]domainGroup, []string) {
+	groups := domainMessages(messages)
+
+	var domains []string
+	for _, d := range groups[1:] {
+		domains = append(domains, d.name)
+	}
+
+	for _, name := range e.writtenDomains() {
+		if !slices.Contains(domains, name) {
+			groups = append(groups, domainGroup{name: name})
+		}
+	}
+	return groups, domains
+}
+
+// writtenDomains returns the domains listed by the messages JSON held by the
+// sink, if it can read it back.
+func (e *Extractor) writtenDomains() []string {
+	rs, ok := e.sink.(ReadSink)
+	if !ok || e.out == "" {
+		return nil
+	}
+
+	raw, err := rs.ReadFile(e.out)
+	if err != nil {
+		return nil
+	}
+
+	var output OutputJSON
+	if err := json.Unmarshal(raw, &output); err != nil {
+		return nil
+	}
+	return slices.DeleteFunc(output.Domains, func(name string) bool {
+		return name == "" || !reDomainName.MatchString(name)
+	})
+}
+
+// domainFile returns the output file of domain: name itself for the default
+// domain, or else name with the domain before its extension, as in
+// messages.admin.json.
+func domainFile(name, domain string) string {
+	if domain == "" {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + domain + ext
+}
+
+// skip reports whether the walk should not descend into the directory or
+// scan the file at rel. A skipped directory is reported with fs.SkipDir.
+func (e *Extractor) skip(rel string, d fs.DirEntry, ignore *gitignore) (bool, error) {
+	if rel == "." {
+		return false, nil
+	}
+
+	skip := (!e.hidden && strings.HasPrefix(d.Name(), ".")) ||
+		matchAny(e.exclude, rel) ||
+		(ignore != nil && ignore.ignored(rel, d.IsDir()))
+
+	switch {
+	case skip && d.IsDir():
+		return true, fs.SkipDir
+	case skip:
+		return true, nil
+	case !d.IsDir() && len(e.include) > 0 && !matchAny(e.include, rel):
+		return true, nil
+	}
+	return false, nil
+}
+
 func (e *Extractor) isTemplate(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 	_, ok := e.exts[ext]
 	return ok
 }
 
-func extractFromContent(ret map[string]*Message, content []byte, relPath string) {
-	for _, id := range reI18n.FindAllIndex(content, -1) {
+// scanner finds translation function calls in template content.
+type scanner struct {
+	re    *regexp.Regexp
+	right []byte
+	funcs map[string]Func
+}
+
+func newScanner(left, right string, funcs []Func) *scanner {
+	names := make([]string, 0, len(funcs))
+	fm := make(map[string]Func, len(funcs))
+	for _, f := range funcs {
+		names = append(names, regexp.QuoteMeta(f.Name))
+		fm[f.Name] = f
+	}
+
+	return &scanner{
+		re:    regexp.MustCompile(regexp.QuoteMeta(left) + `-?\s*(` + strings.Join(names, "|") + `)\s+`),
+		right: []byte(right),
+		funcs: fm,
+	}
+}
+
+// scan adds the messages found in content to ret and returns the warnings
+// about calls that cannot be extracted or look wrong. Warnings on the line of
+// an i18n:ignore comment or the line after it are suppressed.
+func (s *scanner) scan(ret map[string]*Message, content []byte, relPath string) []Warning {
+	if len(s.funcs) == 0 {
+		return nil
+	}
+
+	var (
+		warnings []Warning
+		ignored  = pragmaLines(content)
+	)
+
+	for _, id := range s.re.FindAllSubmatchIndex(content, -1) {
 		start := id[1]
-		end := bytes.Index(content[start:], []byte("}}"))
+		end := bytes.Index(content[start:], s.right)
 		if end < 0 {
 			continue
 		}
 
-		segment := string(content[start : start+end])
-		// find all string literal args in the segment
-		strArgs := reAllStringArgs.FindAllStringSubmatch(segment, -1)
-		if len(strArgs) == 0 {
+		f := s.funcs[string(content[id[2]:id[3]])]
+		args := splitArgs(trimRightMarker(string(content[start : start+end])))
+		position := positionFor(content, start+1, relPath)
+
+		warn := func(msg string) {
+			if _, ok := ignored[lineOf(content, start)]; !ok {
+				warnings = append(warnings, Warning{Position: position, Message: msg})
+			}
+		}
+
+		if f.Arg >= len(args) {
+			warn(f.Name + " called without a message key")
 			continue
 		}
 
-		str := unescape.Replace(strArgs[0][1])
-		position := positionFor(content, start+1, relPath)
+		// a stable key comes with its source text: (Key "checkout.pay" "Pay now")
+		var text string
+		str, ok := stringLiteral(args[f.Arg])
+		if !ok {
+			str, text, ok = keyCall(args[f.Arg])
+		}
+		switch {
+		case !ok:
+			warn(keyWarning(args[f.Arg]))
+			continue
+		case str == "":
+			warn("empty message key")
+			continue
+		}
+		source := str
+		if text != "" {
+			source = text
+		}
+
+		// the remaining arguments besides the key and the language are
+		// passed to the message
+		if msg := formatWarning(source, max(0, len(args)-2)); msg != "" {
+			warn(msg)
+		}
 
 		if existing, ok := ret[str]; ok {
 			// append position
 			existing.Positions = append(existing.Positions, position)
+			existing.HTML = existing.HTML || f.HTML
+			switch {
+			case existing.Message == "" && text != "":
+				existing.Message = text
+				existing.Placeholders = Placeholders(text)
+			case text != "" && text != existing.Message:
+				warn(conflictMessage(str, text, existing.Message))
+			}
 		} else {
-			ret[str] = &Message{ID: str, Positions: []string{position}}
+			ret[str] = &Message{ID: str, Message: text, Positions: []string{position}, HTML: f.HTML, Placeholders: Placeholders(source)}
+		}
+	}
+
+	return warnings
+}
+
+// trimRightMarker removes the trim marker of a right delimiter ("-}}"), a
+// dash preceded by a space, from the end of the action s.
+func trimRightMarker(s string) string {
+	if trimmed, ok := strings.CutSuffix(s, "-"); ok && trimmed != "" && isSpace(trimmed[len(trimmed)-1]) {
+		return trimmed
+	}
+	return s
+}
+
+// splitArgs splits the arguments of a template function call, keeping quoted
+// strings and parenthesized pipelines together. It stops at a top-level pipe.
+func splitArgs(s string) []string {
+	var (
+		args  []string
+		depth int
+		start = -1
+	)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if start < 0 && !isSpace(c) {
+			if depth == 0 && c == '|' {
+				break
+			}
+			start = i
+		}
+
+		switch {
+		case c == '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case c == '`':
+			for i++; i < len(s) && s[i] != '`'; i++ {
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case isSpace(c) && depth == 0 && start >= 0:
+			args = append(args, s[start:i])
+			start = -1
+		}
+	}
+
+	if start >= 0 && start < len(s) {
+		args = append(args, s[start:])
+	}
+
+	return args
+}
+
+// stringLiteral returns the value of a quoted or raw string literal argument.
+func stringLiteral(arg string) (string, bool) {
+	if len(arg) < 2 {
+		return "", false
+	}
+
+	switch {
+	case arg[0] == '`' && arg[len(arg)-1] == '`':
+		return arg[1 : len(arg)-1], true
+	case arg[0] == '"' && arg[len(arg)-1] == '"':
+		if str, err := strconv.Unquote(arg); err == nil {
+			return str, true
 		}
+		return unescape.Replace(arg[1 : len(arg)-1]), true
 	}
+	return "", false
+}
+
+// keyCall returns the key and source text of a message referenced with Key,
+// a (Key "checkout.pay" "Pay now") argument.
+func keyCall(arg string) (key, source string, ok bool) {
+	inner, ok := strings.CutPrefix(arg, "(")
+	if !ok {
+		return "", "", false
+	}
+	if inner, ok = strings.CutSuffix(inner, ")"); !ok {
+		return "", "", false
+	}
+
+	args := splitArgs(inner)
+	if len(args) != 3 || args[0] != "Key" {
+		return "", "", false
+	}
+
+	key, ok = stringLiteral(args[1])
+	if !ok {
+		return "", "", false
+	}
+	source, ok = stringLiteral(args[2])
+	return key, source, ok
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// mergeMessages adds the messages of src to dst, appending positions of
+// messages found in both. It warns about keys with conflicting source texts.
+func mergeMessages(dst, src map[string]*Message) []Warning {
+	var warnings []Warning
+	for _, id := range slices.Sorted(maps.Keys(src)) {
+		msg := src[id]
+		existing, ok := dst[id]
+		if !ok {
+			dst[id] = msg
+			continue
+		}
+
+		existing.Positions = append(existing.Positions, msg.Positions...)
+		existing.HTML = existing.HTML || msg.HTML
+		switch {
+		case existing.Message == "" && msg.Message != "":
+			existing.Message = msg.Message
+			existing.Placeholders = msg.Placeholders
+		case msg.Message != "" && msg.Message != existing.Message:
+			warnings = append(warnings, Warning{Position: msg.Positions[0], Message: conflictMessage(id, msg.Message, existing.Message)})
+		}
+	}
+	return warnings
+}
+
+func conflictMessage(key, text, defined string) string {
+	return fmt.Sprintf("key %q has source text %q, already defined as %q", key, text, defined)
 }
 
 func positionFor(content []byte, pos int, relPath string) string {
@@ -224,6 +1093,48 @@ func positionFor(content []byte, pos int, relPath string) string {
 	return fmt.Sprintf("%s:%d:%d", relPath, lines, col)
 }
 
+// sortPositions sorts file:line:col positions by file, line and column. With
+// fileOnly the line and column are dropped and every file is listed once.
+func sortPositions(positions []string, fileOnly bool) []string {
+	if fileOnly {
+		for i, pos := range positions {
+			positions[i], _, _ = splitPosition(pos)
+		}
+		slices.Sort(positions)
+		return slices.Compact(positions)
+	}
+
+	slices.SortStableFunc(positions, func(a, b string) int {
+		af, al, ac := splitPosition(a)
+		bf, bl, bc := splitPosition(b)
+		return cmp.Or(strings.Compare(af, bf), cmp.Compare(al, bl), cmp.Compare(ac, bc))
+	})
+	return positions
+}
+
+// splitPosition splits a file:line:col position. Unknown line and column
+// numbers are 0.
+func splitPosition(pos string) (file string, line, col int) {
+	file, c, ok := cutLast(pos, ":")
+	if !ok {
+		return pos, 0, 0
+	}
+	file, l, ok := cutLast(file, ":")
+	if !ok {
+		return pos, 0, 0
+	}
+	line, _ = strconv.Atoi(l)
+	col, _ = strconv.Atoi(c)
+	return file, line, col
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
 func strconvQuote(s string) string {
 	var b strings.Builder
 	b.WriteByte('"')
@@ -262,3 +1173,11 @@ func sanitizePkgName(n string) string {
 	}
 	return out
 }
+
+// languageExpr returns the Go expression of tag in the synthetic Go file.
+func languageExpr(tag language.Tag) string {
+	if tag == language.English {
+		return "language.English"
+	}
+	return "language.MustParse(" + strconv.Quote(tag.String()) + ")"
+}
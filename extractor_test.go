@@ -1,14 +1,20 @@
 package i18n
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
 )
 
 // ExtractorTestSuite contains all tests for the extractor functionality
@@ -40,65 +46,130 @@ func (suite *ExtractorTestSuite) SetupTest() {
 
 // TestNewExtractor tests the NewExtractor constructor function
 func (suite *ExtractorTestSuite) TestNewExtractor() {
+	defaultExts := map[string]struct{}{".html": {}, ".htm": {}, ".tmpl": {}, ".gohtml": {}, ".txt": {}, ".tpl": {}}
+
 	tests := []struct {
 		name     string
-		dir      string
-		out      string
-		pkg      string
-		goFile   string
-		exts     []string
+		opts     []ExtractorOption
 		expected *Extractor
 	}{
 		{
-			name:   "basic extractor",
-			dir:    "/tmp",
-			out:    "out.json",
-			pkg:    "main",
-			goFile: "gotext_stub.go",
-			exts:   []string{".html", ".tmpl"},
+			name: "defaults",
+			expected: &Extractor{
+				dir:        ".",
+				pkg:        "main",
+				goFile:     "gotext_stub.go",
+				exts:       defaultExts,
+				left:       "{{",
+				right:      "}}",
+				funcs:      DefaultFuncs,
+				sink:       DirSink{},
+				log:        os.Stdout,
+				sourceLang: language.English,
+			},
+		},
+		{
+			name: "basic extractor",
+			opts: []ExtractorOption{WithDir("/tmp"), WithOut("out.json"), WithPkg("main"), WithGoFile("gotext_stub.go"), WithExts(".html", ".tmpl")},
+			expected: &Extractor{
+				dir:        "/tmp",
+				out:        "out.json",
+				pkg:        "main",
+				goFile:     "gotext_stub.go",
+				exts:       map[string]struct{}{".html": {}, ".tmpl": {}},
+				left:       "{{",
+				right:      "}}",
+				funcs:      DefaultFuncs,
+				sink:       DirSink{},
+				log:        os.Stdout,
+				sourceLang: language.English,
+			},
+		},
+		{
+			name: "no extensions",
+			opts: []ExtractorOption{WithDir("/tmp"), WithGoFile("stub.go"), WithExts()},
+			expected: &Extractor{
+				dir:        "/tmp",
+				pkg:        "main",
+				goFile:     "stub.go",
+				exts:       map[string]struct{}{},
+				left:       "{{",
+				right:      "}}",
+				funcs:      DefaultFuncs,
+				sink:       DirSink{},
+				log:        os.Stdout,
+				sourceLang: language.English,
+			},
+		},
+		{
+			name: "delimiters and functions",
+			opts: []ExtractorOption{WithDelims("[[", "]]"), WithFuncs(Func{Name: "tr", Arg: 1})},
 			expected: &Extractor{
-				dir:    "/tmp",
-				out:    "out.json",
-				pkg:    "main",
-				goFile: "gotext_stub.go",
-				exts:   map[string]struct{}{".html": {}, ".tmpl": {}},
+				dir:        ".",
+				pkg:        "main",
+				goFile:     "gotext_stub.go",
+				exts:       defaultExts,
+				left:       "[[",
+				right:      "]]",
+				funcs:      []Func{{Name: "tr", Arg: 1}},
+				sink:       DirSink{},
+				log:        os.Stdout,
+				sourceLang: language.English,
 			},
 		},
 		{
-			name:   "no extensions",
-			dir:    "/tmp",
-			out:    "",
-			pkg:    "",
-			goFile: "stub.go",
-			exts:   []string{},
+			name: "config with later option override",
+			opts: []ExtractorOption{
+				WithConfig(ExtractorConfig{
+					Dir:        "templates",
+					Out:        "messages.json",
+					Pkg:        "mypackage",
+					GoFile:     "extract.go",
+					Exts:       []string{".gohtml"},
+					LeftDelim:  "[[",
+					RightDelim: "]]",
+					Funcs:      []Func{{Name: "tr", Arg: 0}},
+
+					SourceLanguage: language.German,
+				}),
+				WithPkg("override"),
+				WithSourceLanguage(language.Italian),
+			},
 			expected: &Extractor{
-				dir:    "/tmp",
-				out:    "",
-				pkg:    "",
-				goFile: "stub.go",
-				exts:   map[string]struct{}{},
+				dir:        "templates",
+				out:        "messages.json",
+				pkg:        "override",
+				goFile:     "extract.go",
+				exts:       map[string]struct{}{".gohtml": {}},
+				left:       "[[",
+				right:      "]]",
+				funcs:      []Func{{Name: "tr", Arg: 0}},
+				sink:       DirSink{},
+				log:        os.Stdout,
+				sourceLang: language.Italian,
 			},
 		},
 		{
-			name:   "single extension",
-			dir:    "templates",
-			out:    "messages.json",
-			pkg:    "mypackage",
-			goFile: "extract.go",
-			exts:   []string{".gohtml"},
+			name: "empty config keeps defaults",
+			opts: []ExtractorOption{WithConfig(ExtractorConfig{})},
 			expected: &Extractor{
-				dir:    "templates",
-				out:    "messages.json",
-				pkg:    "mypackage",
-				goFile: "extract.go",
-				exts:   map[string]struct{}{".gohtml": {}},
+				dir:        ".",
+				pkg:        "main",
+				goFile:     "gotext_stub.go",
+				exts:       defaultExts,
+				left:       "{{",
+				right:      "}}",
+				funcs:      DefaultFuncs,
+				sink:       DirSink{},
+				log:        os.Stdout,
+				sourceLang: language.English,
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		suite.Run(tt.name, func() {
-			extractor := NewExtractor(tt.dir, tt.out, tt.pkg, tt.goFile, tt.exts...)
+			extractor := NewExtractor(tt.opts...)
 			assert.Equal(suite.T(), tt.expected, extractor)
 		})
 	}
@@ -106,7 +177,7 @@ func (suite *ExtractorTestSuite) TestNewExtractor() {
 
 // TestIsTemplate tests the isTemplate method
 func (suite *ExtractorTestSuite) TestIsTemplate() {
-	extractor := NewExtractor("", "", "", "", ".html", ".tmpl", ".gohtml")
+	extractor := NewExtractor(WithExts(".html", ".tmpl", ".gohtml"))
 
 	tests := []struct {
 		path     string
@@ -175,6 +246,55 @@ func (suite *ExtractorTestSuite) TestExtractFromContent() {
 			relPath:  "escape.html",
 			expected: map[string]*Message{`Say "Hello"`: {ID: `Say "Hello"`, Positions: []string{"escape.html:1:10"}}},
 		},
+		{
+			name:     "Thtml call",
+			content:  `{{ Thtml .Lang "Read the <a href=\"%s\">terms</a>" .URL }}`,
+			relPath:  "terms.html",
+			expected: map[string]*Message{`Read the <a href="%s">terms</a>`: {ID: `Read the <a href="%s">terms</a>`, Positions: []string{"terms.html:1:11"}, HTML: true}},
+		},
+		{
+			name:    "Thtml and T share a message",
+			content: `{{ T .Lang "Terms" }} {{ Thtml .Lang "Terms" }}`,
+			relPath: "share.html",
+			expected: map[string]*Message{
+				"Terms": {ID: "Terms", Positions: []string{"share.html:1:7", "share.html:1:33"}, HTML: true},
+			},
+		},
+		{
+			name:     "named placeholders",
+			content:  `{{ T .Lang "Hello {name}, you have {count} messages" "name" .Name "count" .Count }}`,
+			relPath:  "named.html",
+			expected: map[string]*Message{"Hello {name}, you have {count} messages": {ID: "Hello {name}, you have {count} messages", Positions: []string{"named.html:1:7"}, Placeholders: []string{"name", "count"}}},
+		},
+		{
+			name:    "keyed messages",
+			content: `{{ T .Lang (Key "checkout.pay" "Pay now") }} {{ T .Lang "checkout.pay" }} {{ T .Lang (Key "checkout.hello" "Hello {name}") "name" .Name }}`,
+			relPath: "keyed.html",
+			expected: map[string]*Message{
+				"checkout.pay":   {ID: "checkout.pay", Message: "Pay now", Positions: []string{"keyed.html:1:7", "keyed.html:1:52"}},
+				"checkout.hello": {ID: "checkout.hello", Message: "Hello {name}", Positions: []string{"keyed.html:1:81"}, Placeholders: []string{"name"}},
+			},
+		},
+		{
+			name:    "dotted ID followed by a string",
+			content: `{{ T .Lang "checkout.pay" "EUR" }}`,
+			relPath: "keyed.html",
+			expected: map[string]*Message{
+				"checkout.pay": {ID: "checkout.pay", Positions: []string{"keyed.html:1:7"}},
+			},
+		},
+		{
+			name:     "string language argument",
+			content:  `{{ T "en" "Hello" }}`,
+			relPath:  "lang.html",
+			expected: map[string]*Message{"Hello": {ID: "Hello", Positions: []string{"lang.html:1:7"}}},
+		},
+		{
+			name:     "raw string key",
+			content:  "{{ T .Lang `Say \"Hi\"` }}",
+			relPath:  "raw.html",
+			expected: map[string]*Message{`Say "Hi"`: {ID: `Say "Hi"`, Positions: []string{"raw.html:1:7"}}},
+		},
 		{
 			name:     "no i18n calls",
 			content:  `<div>Regular HTML content</div>`,
@@ -192,12 +312,54 @@ func (suite *ExtractorTestSuite) TestExtractFromContent() {
 	for _, tt := range tests {
 		suite.Run(tt.name, func() {
 			result := make(map[string]*Message)
-			extractFromContent(result, []byte(tt.content), tt.relPath)
+			newScanner("{{", "}}", DefaultFuncs).scan(result, []byte(tt.content), tt.relPath)
 			assert.Equal(suite.T(), tt.expected, result)
 		})
 	}
 }
 
+// TestScanCustomFuncs tests scanning with custom delimiters and function names
+func (suite *ExtractorTestSuite) TestScanCustomFuncs() {
+	sc := newScanner("[[", "]]", []Func{{Name: "tr", Arg: 1}, {Name: "trp", Arg: 0}, {Name: "trh", Arg: 1, HTML: true}})
+
+	content := `[[ tr .Lang "Hello" ]] [[- trp "Items %d" .Lang .Count -]] [[ trh "en" "<b>Bold</b>" ]] {{ T .Lang "Ignored" }} [[ tr "en" .Key ]]`
+	result := make(map[string]*Message)
+	sc.scan(result, []byte(content), "theme.html")
+
+	assert.Equal(suite.T(), map[string]*Message{
+		"Hello":       {ID: "Hello", Positions: []string{"theme.html:1:8"}},
+		"Items %d":    {ID: "Items %d", Positions: []string{"theme.html:1:33"}},
+		"<b>Bold</b>": {ID: "<b>Bold</b>", Positions: []string{"theme.html:1:68"}, HTML: true},
+	}, result)
+
+	empty := make(map[string]*Message)
+	newScanner("{{", "}}", nil).scan(empty, []byte(content), "theme.html")
+	assert.Empty(suite.T(), empty)
+}
+
+// TestSplitArgs tests the splitArgs function
+func (suite *ExtractorTestSuite) TestSplitArgs() {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{` .Lang "Hello" `, []string{".Lang", `"Hello"`}},
+		{`.Lang "Hello world" .Name`, []string{".Lang", `"Hello world"`, ".Name"}},
+		{`.Lang "Say \"hi there\"" 1`, []string{".Lang", `"Say \"hi there\""`, "1"}},
+		{".Lang `raw string` 1", []string{".Lang", "`raw string`", "1"}},
+		{`(index .Langs 0) "Hello" (printf "%s x" .A)`, []string{"(index .Langs 0)", `"Hello"`, `(printf "%s x" .A)`}},
+		{`.Lang "Hello" | html`, []string{".Lang", `"Hello"`}},
+		{`.Lang "a|b" -`, []string{".Lang", `"a|b"`, "-"}},
+		{``, nil},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.input, func() {
+			assert.Equal(suite.T(), tt.expected, splitArgs(tt.input))
+		})
+	}
+}
+
 // TestPositionFor tests the positionFor function
 func (suite *ExtractorTestSuite) TestPositionFor() {
 	tests := []struct {
@@ -398,9 +560,10 @@ func (suite *ExtractorTestSuite) TestExtract() {
 	require.NoError(suite.T(), err)
 
 	// Test extraction
-	extractor := NewExtractor(suite.tempDir, "", "testpkg", filepath.Join(suite.tempDir, "gotext.go"), ".html", ".tmpl")
-	messages, err := extractor.extract()
+	extractor := NewExtractor(WithDir(suite.tempDir), WithPkg("testpkg"), WithGoFile(filepath.Join(suite.tempDir, "gotext.go")), WithExts(".html", ".tmpl"))
+	res, err := extractor.extract()
 	require.NoError(suite.T(), err)
+	messages := res.Messages
 
 	// Verify extracted messages
 	expectedMessages := []*Message{
@@ -418,6 +581,614 @@ func (suite *ExtractorTestSuite) TestExtract() {
 	}
 }
 
+// TestExtractWalkFilters tests include/exclude globs, .gitignore and hidden directories
+func (suite *ExtractorTestSuite) TestExtractWalkFilters() {
+	files := map[string]string{
+		".gitignore":                      "generated/\n*.bak.html\n",
+		"index.html":                      `{{ T .Lang "Index" }}`,
+		"index.bak.html":                  `{{ T .Lang "Backup" }}`,
+		"generated/out.html":              `{{ T .Lang "Generated" }}`,
+		"node_modules/lib/widget.html":    `{{ T .Lang "Third party" }}`,
+		"vendor/x/page.html":              `{{ T .Lang "Vendored" }}`,
+		".cache/page.html":                `{{ T .Lang "Hidden" }}`,
+		"admin/.gitignore":                "local.html\n",
+		"admin/local.html":                `{{ T .Lang "Local" }}`,
+		"admin/dashboard.html":            `{{ T .Lang "Dashboard" }}`,
+		"shop/cart.html":                  `{{ T .Lang "Cart" }}`,
+		"shop/emails/confirmation.gohtml": `{{ T .Lang "Confirmation" }}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(suite.tempDir, name)
+		require.NoError(suite.T(), os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(suite.T(), os.WriteFile(path, []byte(content), 0644))
+	}
+
+	ids := func(opts ...ExtractorOption) []string {
+		opts = append([]ExtractorOption{WithDir(suite.tempDir), WithExts(".html", ".gohtml")}, opts...)
+		res, err := NewExtractor(opts...).extract()
+		require.NoError(suite.T(), err)
+
+		var ids []string
+		for _, m := range res.Messages {
+			ids = append(ids, m.ID)
+		}
+		return ids
+	}
+
+	suite.Run("defaults honor gitignore and skip hidden", func() {
+		assert.Equal(suite.T(), []string{"Cart", "Confirmation", "Dashboard", "Index", "Third party", "Vendored"}, ids())
+	})
+
+	suite.Run("exclude", func() {
+		assert.Equal(suite.T(), []string{"Cart", "Confirmation", "Dashboard", "Index"}, ids(WithExclude("**/node_modules", "vendor")))
+	})
+
+	suite.Run("include", func() {
+		assert.Equal(suite.T(), []string{"Cart", "Confirmation"}, ids(WithInclude("shop/**")))
+		assert.Equal(suite.T(), []string{"Confirmation"}, ids(WithInclude("**/*.gohtml")))
+	})
+
+	suite.Run("without gitignore", func() {
+		assert.Equal(suite.T(), []string{"Backup", "Cart", "Confirmation", "Dashboard", "Generated", "Index", "Local", "Third party", "Vendored"}, ids(WithGitignore(false)))
+	})
+
+	suite.Run("hidden", func() {
+		assert.Contains(suite.T(), ids(WithHidden(true)), "Hidden")
+	})
+}
+
+// TestExtractRepositoryGitignore tests that the .gitignore files above the
+// scanned directory apply up to the root of its repository
+func (suite *ExtractorTestSuite) TestExtractRepositoryGitignore() {
+	files := map[string]string{
+		".gitignore":                "node_modules/\n/build/\n",
+		"web/.gitignore":            "*.gen.html\n",
+		"web/index.html":            `{{ T .Lang "Index" }}`,
+		"web/page.gen.html":         `{{ T .Lang "Generated" }}`,
+		"web/build/page.html":       `{{ T .Lang "Build" }}`,
+		"web/node_modules/lib.html": `{{ T .Lang "Third party" }}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(suite.tempDir, name)
+		require.NoError(suite.T(), os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(suite.T(), os.WriteFile(path, []byte(content), 0644))
+	}
+
+	ids := func() []string {
+		res, err := NewExtractor(WithDir(filepath.Join(suite.tempDir, "web")), WithExts(".html")).extract()
+		require.NoError(suite.T(), err)
+
+		var ids []string
+		for _, m := range res.Messages {
+			ids = append(ids, m.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(suite.T(), []string{"Build", "Index", "Third party"}, ids())
+
+	require.NoError(suite.T(), os.Mkdir(filepath.Join(suite.tempDir, ".git"), 0755))
+	assert.Equal(suite.T(), []string{"Build", "Index"}, ids())
+}
+
+// TestExtractParallelDeterministic tests that concurrent scanning merges results in walk order
+func (suite *ExtractorTestSuite) TestExtractParallelDeterministic() {
+	for i := range 50 {
+		dir := filepath.Join(suite.tempDir, fmt.Sprintf("d%02d", i))
+		require.NoError(suite.T(), os.MkdirAll(dir, 0755))
+		content := fmt.Sprintf(`{{ T .Lang "Shared" }} {{ T .Lang "Only %d" }} {{ Thtml .Lang "Shared" }}`, i)
+		require.NoError(suite.T(), os.WriteFile(filepath.Join(dir, "page.html"), []byte(content), 0644))
+	}
+
+	sequential, err := NewExtractor(WithDir(suite.tempDir), WithExts(".html"), WithWorkers(1)).extract()
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), sequential.Messages, 51)
+
+	shared := sequential.Messages[50]
+	assert.Equal(suite.T(), "Shared", shared.ID)
+	assert.True(suite.T(), shared.HTML)
+	require.Len(suite.T(), shared.Positions, 100)
+	assert.Equal(suite.T(), filepath.Join("d00", "page.html")+":1:7", shared.Positions[0])
+	assert.Equal(suite.T(), filepath.Join("d49", "page.html")+":1:58", shared.Positions[99])
+
+	for range 5 {
+		parallel, err := NewExtractor(WithDir(suite.tempDir), WithExts(".html"), WithWorkers(8)).extract()
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), sequential.Messages, parallel.Messages)
+	}
+}
+
+// TestExtractWithCache tests that unchanged templates are served from the cache
+func (suite *ExtractorTestSuite) TestExtractWithCache() {
+	cache := filepath.Join(suite.tempDir, ".i18n-cache")
+	page := filepath.Join(suite.tempDir, "page.html")
+	require.NoError(suite.T(), os.WriteFile(page, []byte(`{{ T .Lang "Hello" }}`), 0644))
+	require.NoError(suite.T(), os.WriteFile(filepath.Join(suite.tempDir, "other.html"), []byte(`{{ T .Lang "Hello" }} {{ T .Lang "Other" }}`), 0644))
+
+	extractor := NewExtractor(WithDir(suite.tempDir), WithExts(".html"), WithCache(cache))
+
+	first, err := extractor.extract()
+	require.NoError(suite.T(), err)
+	assert.FileExists(suite.T(), cache)
+
+	stored := loadCache(cache, extractor.cacheSettings())
+	assert.Len(suite.T(), stored.Files, 2)
+
+	second, err := extractor.extract()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), first.Messages, second.Messages)
+	assert.Equal(suite.T(), 2, second.Cached)
+
+	// a stale cache entry proves the file was not scanned again
+	stored.Files["page.html"].Messages[0].ID = "From cache"
+	stored.Files["page.html"].Warnings = []Warning{{Position: "page.html:1:7", Message: "from cache"}}
+	stored.changed = true
+	require.NoError(suite.T(), stored.save(cache))
+
+	cached, err := extractor.extract()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "From cache", cached.Messages[0].ID)
+	assert.Equal(suite.T(), []Warning{{Position: "page.html:1:7", Message: "from cache"}}, cached.Warnings)
+
+	// modified content is scanned again
+	require.NoError(suite.T(), os.WriteFile(page, []byte(`{{ T .Lang "Changed" }}`), 0644))
+	changed, err := extractor.extract()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Changed", "Hello", "Other"}, []string{changed.Messages[0].ID, changed.Messages[1].ID, changed.Messages[2].ID})
+	assert.Equal(suite.T(), 1, changed.Cached)
+
+	// other settings invalidate every entry
+	custom, err := NewExtractor(WithDir(suite.tempDir), WithExts(".html"), WithCache(cache), WithFuncs(Func{Name: "tr", Arg: 1})).extract()
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), custom.Messages)
+}
+
+// TestExtractFromFS tests extracting from an fs.FS into a MemorySink
+func (suite *ExtractorTestSuite) TestExtractFromFS() {
+	fsys := fstest.MapFS{
+		"web/templates/index.html":      {Data: []byte(`{{ T .Lang "Hello" }}`)},
+		"web/templates/shop/cart.html":  {Data: []byte(`{{ T .Lang "Cart" }} {{ T .Lang "Hello" }}`)},
+		"web/templates/vendor/lib.html": {Data: []byte(`{{ T .Lang "Vendored" }}`)},
+		"web/templates/.gitignore":      {Data: []byte("vendor/\n")},
+		"web/templates/.hidden/x.html":  {Data: []byte(`{{ T .Lang "Hidden" }}`)},
+		"web/static/ignored.html":       {Data: []byte(`{{ T .Lang "Static" }}`)},
+	}
+	sink := &MemorySink{}
+
+	extractor := NewExtractor(WithFS(fsys), WithDir("web/templates"), WithOut("out/messages.json"), WithGoFile("out/gotext.go"), WithExts(".html"), WithSink(sink))
+	require.NoError(suite.T(), extractor.Extract())
+
+	data, err := sink.ReadFile("out/messages.json")
+	require.NoError(suite.T(), err)
+
+	var out OutputJSON
+	require.NoError(suite.T(), json.Unmarshal(data, &out))
+	messages := out.Messages
+	require.Len(suite.T(), messages, 2)
+	assert.Equal(suite.T(), "Cart", messages[0].ID)
+	assert.Equal(suite.T(), []string{"shop/cart.html:1:7"}, messages[0].Positions)
+	assert.Equal(suite.T(), []string{"index.html:1:7", "shop/cart.html:1:28"}, messages[1].Positions)
+
+	goFile, err := sink.ReadFile("out/gotext.go")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(goFile), `"Hello"`)
+	assert.NoFileExists(suite.T(), "out/messages.json")
+}
+
+// TestExtractDomains tests writing one messages JSON and Go file per domain
+func (suite *ExtractorTestSuite) TestExtractDomains() {
+	fsys := fstest.MapFS{
+		"index.html":          {Data: []byte(`{{ T .Lang "Home" }}`)},
+		"admin/users.html":    {Data: []byte(`{{ T .Lang "Save" }} {{ T .Lang "Home" }}`)},
+		"shop/cart.html":      {Data: []byte(`{{ T .Lang "Save" }}`)},
+		"shop/checkout.html":  {Data: []byte(`{{/* i18n:domain check-out */}}{{ T .Lang "Pay" }}`)},
+		"shop/legacy/a.html":  {Data: []byte(`{{ T .Lang "Legacy" }}`)},
+		"shared/footer.html":  {Data: []byte(`{{/* i18n:domain admin */}}{{ T .Lang "Footer" }}`)},
+		"shared/invalid.html": {Data: []byte(`{{/* i18n:domain ../x */}}{{ T .Lang "Shared" }}`)},
+	}
+	sink := &MemorySink{}
+
+	extractor := NewExtractor(
+		WithFS(fsys), WithOut("out/messages.json"), WithGoFile("out/gotext.go"), WithExts(".html"), WithSink(sink), WithLog(io.Discard),
+		WithDomains(map[string][]string{"admin": {"admin/**"}, "shop": {"shop/**"}, "xlegacy": {"shop/legacy/**"}}),
+	)
+	require.NoError(suite.T(), extractor.Extract())
+	require.NoError(suite.T(), extractor.Check())
+
+	res, err := extractor.ExtractMessages()
+	require.NoError(suite.T(), err)
+
+	ids := make([]string, len(res.Messages))
+	for i, msg := range res.Messages {
+		ids[i] = msg.Domain + ":" + msg.ID
+	}
+	assert.Equal(suite.T(), []string{":Home", ":Shared", "admin:Footer", "admin:Home", "admin:Save", "check-out:Pay", "shop:Legacy", "shop:Save"}, ids)
+
+	for name, want := range map[string][]string{
+		"out/messages.json":           {"Home", "Shared"},
+		"out/messages.admin.json":     {"Footer", "Home", "Save"},
+		"out/messages.check-out.json": {"Pay"},
+		"out/messages.shop.json":      {"Legacy", "Save"},
+	} {
+		data, err := sink.ReadFile(name)
+		require.NoError(suite.T(), err, name)
+
+		var out OutputJSON
+		require.NoError(suite.T(), json.Unmarshal(data, &out))
+
+		var got []string
+		for _, msg := range out.Messages {
+			got = append(got, msg.ID)
+		}
+		assert.Equal(suite.T(), want, got, name)
+	}
+
+	data, err := sink.ReadFile("out/messages.admin.json")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(data), `"domain": "admin"`)
+
+	goFile, err := sink.ReadFile("out/gotext.check-out.go")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(goFile), "func _i18n_extract_check_out() {")
+	assert.Contains(suite.T(), string(goFile), `_ = p.Sprintf("Pay")`)
+
+	goFile, err = sink.ReadFile("out/gotext.go")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(goFile), "func _i18n_extract() {")
+
+	suite.Run("only domains", func() {
+		sink := &MemorySink{}
+		extractor := NewExtractor(WithFS(fstest.MapFS{"admin/a.html": {Data: []byte(`{{ T .Lang "Save" }}`)}}), WithOut("messages.json"), WithGoFile(""), WithExts(".html"), WithSink(sink), WithLog(io.Discard), WithDomains(map[string][]string{"admin": {"admin/**"}}))
+		require.NoError(suite.T(), extractor.Extract())
+
+		data, err := sink.ReadFile("messages.json")
+		require.NoError(suite.T(), err)
+		assert.JSONEq(suite.T(), `{"language": "en", "domains": ["admin"], "messages": null}`, string(data))
+		_, err = sink.ReadFile("messages.admin.json")
+		assert.NoError(suite.T(), err)
+	})
+
+	suite.Run("domains with the same Go identifier", func() {
+		fsys := fstest.MapFS{
+			"a.html": {Data: []byte(`{{/* i18n:domain check-out */}}{{ T .Lang "Pay" }}`)},
+			"b.html": {Data: []byte(`{{/* i18n:domain check_out */}}{{ T .Lang "Cancel" }}`)},
+		}
+		extractor := NewExtractor(WithFS(fsys), WithOut("messages.json"), WithGoFile("gotext.go"), WithExts(".html"), WithSink(&MemorySink{}), WithLog(io.Discard))
+		assert.EqualError(suite.T(), extractor.Extract(), `domains "check-out" and "check_out" have the same Go identifier _i18n_extract_check_out`)
+
+		extractor = NewExtractor(WithFS(fsys), WithOut("messages.json"), WithGoFile(""), WithExts(".html"), WithSink(&MemorySink{}), WithLog(io.Discard))
+		assert.NoError(suite.T(), extractor.Extract())
+	})
+
+	suite.Run("stale domains", func() {
+		messageIDs := func(sink *MemorySink, name string) []string {
+			data, err := sink.ReadFile(name)
+			require.NoError(suite.T(), err, name)
+
+			var out OutputJSON
+			require.NoError(suite.T(), json.Unmarshal(data, &out))
+
+			ids := []string{}
+			for _, msg := range out.Messages {
+				ids = append(ids, msg.ID)
+			}
+			return ids
+		}
+
+		sink := &MemorySink{}
+		fsys := fstest.MapFS{
+			"index.html":   {Data: []byte(`{{ T .Lang "Shop" }}`)},
+			"admin/a.html": {Data: []byte(`{{ T .Lang "Admin" }}`)},
+			"x.html":       {Data: []byte(`{{/* i18n:domain x */}}{{ T .Lang "X" }}`)},
+		}
+		opts := []ExtractorOption{WithFS(fsys), WithOut("m.json"), WithGoFile("m.go"), WithExts(".html"), WithSink(sink), WithLog(io.Discard)}
+
+		require.NoError(suite.T(), NewExtractor(append(opts, WithDomains(map[string][]string{"admin": {"admin/**"}}))...).Extract())
+		assert.Equal(suite.T(), []string{"Shop"}, messageIDs(sink, "m.json"))
+		assert.Equal(suite.T(), []string{"X"}, messageIDs(sink, "m.x.json"))
+
+		// every template mapped to admin
+		all := NewExtractor(append(opts, WithDomains(map[string][]string{"admin": {"**"}}))...)
+		assert.ErrorIs(suite.T(), all.Check(), ErrOutOfDate)
+		require.NoError(suite.T(), all.Extract())
+		require.NoError(suite.T(), all.Check())
+		assert.Equal(suite.T(), []string{}, messageIDs(sink, "m.json"))
+		assert.Equal(suite.T(), []string{"Admin", "Shop"}, messageIDs(sink, "m.admin.json"))
+
+		// the i18n:domain declaration removed
+		fsys["x.html"] = &fstest.MapFile{Data: []byte(`{{ T .Lang "X" }}`)}
+		admin := NewExtractor(append(opts, WithDomains(map[string][]string{"admin": {"admin/**"}}))...)
+		err := admin.Check()
+		assert.ErrorIs(suite.T(), err, ErrOutOfDate)
+		assert.ErrorContains(suite.T(), err, "m.x.json")
+		assert.ErrorContains(suite.T(), err, "m.x.go")
+
+		require.NoError(suite.T(), admin.Extract())
+		require.NoError(suite.T(), admin.Check())
+		assert.Equal(suite.T(), []string{"Shop", "X"}, messageIDs(sink, "m.json"))
+		assert.Equal(suite.T(), []string{"Admin"}, messageIDs(sink, "m.admin.json"))
+		assert.Equal(suite.T(), []string{}, messageIDs(sink, "m.x.json"))
+
+		data, err := sink.ReadFile("m.json")
+		require.NoError(suite.T(), err)
+		var out OutputJSON
+		require.NoError(suite.T(), json.Unmarshal(data, &out))
+		assert.Equal(suite.T(), []string{"admin"}, out.Domains)
+	})
+
+	suite.Run("invalid domain", func() {
+		_, err := NewExtractor(WithFS(fsys), WithLog(io.Discard), WithDomains(map[string][]string{"a/b": {"**"}})).ExtractMessages()
+		assert.ErrorContains(suite.T(), err, `invalid domain "a/b"`)
+	})
+}
+
+// TestExtractModules tests scanning the templates of required Go modules
+func (suite *ExtractorTestSuite) TestExtractModules() {
+	root := suite.T().TempDir()
+	files := map[string]string{
+		"app/go.mod":             "module example.com/app\n\ngo 1.25\n\nrequire example.com/ui v0.0.0\n\nreplace example.com/ui => ../ui\n",
+		"app/index.html":         `{{ T .Lang "Home" }}`,
+		"ui/go.mod":              "module example.com/ui\n\ngo 1.25\n",
+		"ui/button.html":         `{{ T .Lang "Save" }}`,
+		"ui/internal/debug.html": `{{ T .Lang "Debug" }}`,
+		"ui/.hidden/x.html":      `{{ T .Lang "Hidden" }}`,
+	}
+	for name, content := range files {
+		require.NoError(suite.T(), os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755))
+		require.NoError(suite.T(), os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+
+	opts := []ExtractorOption{WithDir(filepath.Join(root, "app")), WithExts(".html"), WithLog(io.Discard)}
+
+	res, err := NewExtractor(opts...).ExtractMessages()
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res.Messages, 1)
+
+	res, err = NewExtractor(append(opts,
+		WithModules("example.com/ui"),
+		WithExclude("example.com/ui/internal"),
+		WithDomains(map[string][]string{"ui": {"example.com/ui/**"}}),
+	)...).ExtractMessages()
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res.Messages, 2)
+	assert.Equal(suite.T(), "Home", res.Messages[0].ID)
+	assert.Equal(suite.T(), "Save", res.Messages[1].ID)
+	assert.Equal(suite.T(), "ui", res.Messages[1].Domain)
+	assert.Equal(suite.T(), []string{"example.com/ui/button.html:1:7"}, res.Messages[1].Positions)
+
+	_, err = NewExtractor(append(opts, WithModules("example.com/missing"))...).ExtractMessages()
+	assert.ErrorContains(suite.T(), err, "module example.com/missing")
+}
+
+// TestExtractSkipsVendor tests that vendored dependencies are not scanned
+func (suite *ExtractorTestSuite) TestExtractSkipsVendor() {
+	fsys := fstest.MapFS{
+		"index.html":                         {Data: []byte(`{{ T .Lang "Home" }}`)},
+		"vendor/modules.txt":                 {Data: []byte("# example.com/ui v1.0.0\n")},
+		"vendor/example.com/ui/a.html":       {Data: []byte(`{{ T .Lang "Vendored" }}`)},
+		"themes/vendor/without-modules.html": {Data: []byte(`{{ T .Lang "Theme" }}`)},
+	}
+
+	res, err := NewExtractor(WithFS(fsys), WithExts(".html"), WithLog(io.Discard)).ExtractMessages()
+	require.NoError(suite.T(), err)
+
+	ids := make([]string, len(res.Messages))
+	for i, msg := range res.Messages {
+		ids[i] = msg.ID
+	}
+	assert.Equal(suite.T(), []string{"Home", "Theme"}, ids)
+}
+
+// TestExtractMessages tests the public extraction result and log output
+func (suite *ExtractorTestSuite) TestExtractMessages() {
+	require.NoError(suite.T(), os.WriteFile(filepath.Join(suite.tempDir, "a.html"), []byte(`{{ T .Lang (Key "cart.title" "Cart") }} {{ T .Lang (Key "cart.title" "Basket") }}`), 0644))
+	require.NoError(suite.T(), os.WriteFile(filepath.Join(suite.tempDir, "b.html"), []byte(`{{ T .Lang (Key "cart.title" "Your cart") }} {{ T .Lang "Hello" }}`), 0644))
+
+	var log bytes.Buffer
+	out := filepath.Join(suite.tempDir, "messages.json")
+	extractor := NewExtractor(WithDir(suite.tempDir), WithOut(out), WithGoFile(filepath.Join(suite.tempDir, "gotext.go")), WithExts(".html"), WithWorkers(2), WithLog(&log))
+
+	res, err := extractor.ExtractMessages()
+	require.NoError(suite.T(), err)
+	assert.NoFileExists(suite.T(), out)
+
+	require.Len(suite.T(), res.Messages, 2)
+	assert.Equal(suite.T(), "Cart", res.Messages[1].Message)
+	assert.Equal(suite.T(), 2, res.Files)
+	assert.Equal(suite.T(), 0, res.Cached)
+	assert.Equal(suite.T(), 2, res.Workers)
+	assert.Equal(suite.T(), []Warning{
+		{Position: "a.html:1:47", Message: `key "cart.title" has source text "Basket", already defined as "Cart"`},
+		{Position: "b.html:1:7", Message: `key "cart.title" has source text "Your cart", already defined as "Cart"`},
+	}, res.Warnings)
+
+	assert.Contains(suite.T(), log.String(), `warning: a.html:1:47: key "cart.title"`)
+	assert.Contains(suite.T(), log.String(), "Scanned 2 templates (0 cached)")
+
+	log.Reset()
+	require.NoError(suite.T(), extractor.Extract())
+	assert.FileExists(suite.T(), out)
+	assert.Contains(suite.T(), log.String(), "Wrote 2 messages")
+}
+
+// TestScanWarnings tests warnings for calls that cannot be extracted
+func (suite *ExtractorTestSuite) TestScanWarnings() {
+	content := `{{ T .Lang .Key }}
+{{ T .Lang (print "shop." .Name) }}
+{{ T .Lang "" }}
+{{ T .Lang "Hello %s" }}
+{{ T .Lang "Hello {name}" (dict "name" .Name) }}
+{{ T .Lang "50% off for {name}" "name" .Name }}
+{{/* i18n:ignore */}}
+{{ T .Lang .Safe }}
+{{ T .Lang }}`
+
+	result := make(map[string]*Message)
+	warnings := newScanner("{{", "}}", DefaultFuncs).scan(result, []byte(content), "page.html")
+
+	assert.Equal(suite.T(), []Warning{
+		{Position: "page.html:1:7", Message: "dynamic message key .Key cannot be extracted"},
+		{Position: "page.html:2:7", Message: `message key (print "shop." .Name) is concatenated at runtime and cannot be extracted`},
+		{Position: "page.html:3:7", Message: "empty message key"},
+		{Position: "page.html:4:7", Message: `message "Hello %s" has 1 format verbs but 0 arguments`},
+		{Position: "page.html:9:7", Message: "T called without a message key"},
+	}, warnings)
+	assert.Len(suite.T(), result, 3)
+
+	trimmed := `{{- T .Lang "Hello" -}}
+{{ T .Lang "Hi %s" .Name -}}
+{{ T .Lang (Key "checkout.pay" "Pay") -}}
+{{ T .Lang "Bye %s" .Name	-}}`
+
+	result = make(map[string]*Message)
+	assert.Empty(suite.T(), newScanner("{{", "}}", DefaultFuncs).scan(result, []byte(trimmed), "trimmed.html"))
+	assert.Len(suite.T(), result, 4)
+	assert.Equal(suite.T(), "Pay", result["checkout.pay"].Message)
+}
+
+// TestExtractStrict tests that strict mode fails on warnings without writing files
+func (suite *ExtractorTestSuite) TestExtractStrict() {
+	out := filepath.Join(suite.tempDir, "messages.json")
+	goFile := filepath.Join(suite.tempDir, "gotext.go")
+	require.NoError(suite.T(), os.WriteFile(filepath.Join(suite.tempDir, "page.html"), []byte(`{{ T .Lang "Hello" }} {{ T .Lang .Key }}`), 0644))
+
+	err := NewExtractor(WithDir(suite.tempDir), WithOut(out), WithGoFile(goFile), WithExts(".html"), WithStrict(true), WithLog(io.Discard)).Extract()
+	assert.EqualError(suite.T(), err, "strict mode: 1 extraction warnings")
+	assert.NoFileExists(suite.T(), out)
+	assert.NoFileExists(suite.T(), goFile)
+
+	require.NoError(suite.T(), NewExtractor(WithDir(suite.tempDir), WithOut(out), WithGoFile(goFile), WithExts(".html"), WithLog(io.Discard)).Extract())
+	assert.FileExists(suite.T(), out)
+}
+
+// TestSortPositions tests position ordering and file-only positions
+func (suite *ExtractorTestSuite) TestSortPositions() {
+	positions := func() []string {
+		return []string{"b.html:10:1", "a/b.html:1:1", "b.html:2:15", "b.html:2:3", "a.html:?:?", "a.html:1:1"}
+	}
+
+	assert.Equal(suite.T(), []string{"a.html:?:?", "a.html:1:1", "a/b.html:1:1", "b.html:2:3", "b.html:2:15", "b.html:10:1"}, sortPositions(positions(), false))
+	assert.Equal(suite.T(), []string{"a.html", "a/b.html", "b.html"}, sortPositions(positions(), true))
+}
+
+// TestExtractStableOutput tests that unchanged output files are not rewritten
+func (suite *ExtractorTestSuite) TestExtractStableOutput() {
+	require.NoError(suite.T(), os.MkdirAll(filepath.Join(suite.tempDir, "a"), 0755))
+	require.NoError(suite.T(), os.WriteFile(filepath.Join(suite.tempDir, "a", "z.html"), []byte(`{{ T .Lang "Hello" }}`), 0644))
+	require.NoError(suite.T(), os.WriteFile(filepath.Join(suite.tempDir, "a.html"), []byte("\n{{ T .Lang \"Hello\" }} {{ T .Lang \"Hello\" }}"), 0644))
+
+	var log bytes.Buffer
+	out := filepath.Join(suite.tempDir, "messages.json")
+	goFile := filepath.Join(suite.tempDir, "gotext.go")
+	opts := []ExtractorOption{WithDir(suite.tempDir), WithOut(out), WithGoFile(goFile), WithExts(".html"), WithLog(&log)}
+
+	require.NoError(suite.T(), NewExtractor(opts...).Extract())
+
+	data, err := os.ReadFile(out)
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), bytes.HasSuffix(data, []byte("}\n")))
+
+	var outputJSON OutputJSON
+	require.NoError(suite.T(), json.Unmarshal(data, &outputJSON))
+	assert.Equal(suite.T(), []string{"a.html:2:7", "a.html:2:29", "a/z.html:1:7"}, outputJSON.Messages[0].Positions)
+
+	log.Reset()
+	require.NoError(suite.T(), NewExtractor(opts...).Extract())
+	assert.Contains(suite.T(), log.String(), out+" is up to date")
+	assert.Contains(suite.T(), log.String(), goFile+" is up to date")
+
+	require.NoError(suite.T(), NewExtractor(append(opts, WithFilePositions(true))...).Extract())
+	data, err = os.ReadFile(out)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), json.Unmarshal(data, &outputJSON))
+	assert.Equal(suite.T(), []string{"a.html", "a/z.html"}, outputJSON.Messages[0].Positions)
+}
+
+// TestCheck tests comparing the outputs with the existing files
+func (suite *ExtractorTestSuite) TestCheck() {
+	page := filepath.Join(suite.tempDir, "page.html")
+	require.NoError(suite.T(), os.WriteFile(page, []byte(`{{ T .Lang "Hello" }}`), 0644))
+
+	var log bytes.Buffer
+	out := filepath.Join(suite.tempDir, "messages.json")
+	goFile := filepath.Join(suite.tempDir, "gotext.go")
+	extractor := NewExtractor(WithDir(suite.tempDir), WithOut(out), WithGoFile(goFile), WithExts(".html"), WithLog(&log))
+
+	err := extractor.Check()
+	assert.ErrorIs(suite.T(), err, ErrOutOfDate)
+	assert.NoFileExists(suite.T(), out)
+	assert.NoFileExists(suite.T(), goFile)
+
+	require.NoError(suite.T(), extractor.Extract())
+	require.NoError(suite.T(), extractor.Check())
+
+	require.NoError(suite.T(), os.WriteFile(page, []byte(`{{ T .Lang "Hello" }} {{ T .Lang "World" }}`), 0644))
+	log.Reset()
+
+	err = extractor.Check()
+	assert.EqualError(suite.T(), err, ErrOutOfDate.Error()+": "+out+", "+goFile)
+	assert.Contains(suite.T(), log.String(), "--- "+out+"\n+++ "+out+" (extracted)\n")
+	assert.Contains(suite.T(), log.String(), `+      "id": "World",`)
+	assert.Contains(suite.T(), log.String(), `+	_ = p.Sprintf("World")`)
+
+	data, err := os.ReadFile(out)
+	require.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), string(data), "World")
+
+	suite.Run("sink without ReadFile", func() {
+		err := NewExtractor(WithDir(suite.tempDir), WithSink(writeOnlySink{}), WithLog(io.Discard)).Check()
+		assert.EqualError(suite.T(), err, "check: sink cannot read files")
+	})
+}
+
+type writeOnlySink struct{}
+
+func (writeOnlySink) WriteFile(string, []byte) error { return nil }
+
+// TestReadMessagesFile tests reading the messages JSON back
+func (suite *ExtractorTestSuite) TestReadMessagesFile() {
+	out := filepath.Join(suite.tempDir, "messages.json")
+	messages := []*Message{{ID: "checkout.pay", Message: "Pay {amount}", Positions: []string{"a.html:1:1"}, Placeholders: []string{"amount"}}}
+	require.NoError(suite.T(), NewExtractor(WithOut(out), WithLog(io.Discard)).saveMessages("", messages))
+
+	read, err := ReadMessagesFile(out)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), messages, read)
+
+	admin := []*Message{{ID: "Save", Domain: "admin"}}
+	require.NoError(suite.T(), NewExtractor(WithOut(out), WithLog(io.Discard)).saveMessages("admin", admin))
+
+	read, err = ReadMessagesFile(filepath.Join(suite.tempDir, "messages.admin.json"))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), admin, read)
+
+	require.NoError(suite.T(), os.WriteFile(out, []byte("{"), 0644))
+	_, err = ReadMessagesFile(out)
+	assert.ErrorContains(suite.T(), err, out)
+}
+
+// TestMergeMessages tests the mergeMessages function
+func (suite *ExtractorTestSuite) TestMergeMessages() {
+	dst := map[string]*Message{
+		"checkout.pay": {ID: "checkout.pay", Positions: []string{"a.html:1:1"}},
+	}
+	warnings := mergeMessages(dst, map[string]*Message{
+		"checkout.pay": {ID: "checkout.pay", Message: "Pay {amount}", Positions: []string{"b.html:1:1"}, HTML: true, Placeholders: []string{"amount"}},
+		"Hello":        {ID: "Hello", Positions: []string{"b.html:2:1"}},
+	})
+	assert.Empty(suite.T(), warnings)
+
+	assert.Equal(suite.T(), map[string]*Message{
+		"checkout.pay": {ID: "checkout.pay", Message: "Pay {amount}", Positions: []string{"a.html:1:1", "b.html:1:1"}, HTML: true, Placeholders: []string{"amount"}},
+		"Hello":        {ID: "Hello", Positions: []string{"b.html:2:1"}},
+	}, dst)
+
+	warnings = mergeMessages(dst, map[string]*Message{
+		"checkout.pay": {ID: "checkout.pay", Message: "Pay now", Positions: []string{"c.html:3:4"}},
+	})
+	assert.Equal(suite.T(), []Warning{{Position: "c.html:3:4", Message: `key "checkout.pay" has source text "Pay now", already defined as "Pay {amount}"`}}, warnings)
+}
+
 // TestSaveMessages tests the saveMessages functionality
 func (suite *ExtractorTestSuite) TestSaveMessages() {
 	messages := []*Message{
@@ -426,9 +1197,9 @@ func (suite *ExtractorTestSuite) TestSaveMessages() {
 	}
 
 	outputFile := filepath.Join(suite.tempDir, "messages.json")
-	extractor := NewExtractor("", outputFile, "test", "test.go")
+	extractor := NewExtractor(WithOut(outputFile), WithPkg("test"), WithGoFile("test.go"))
 
-	err := extractor.saveMessages(messages)
+	err := extractor.saveMessages("", messages)
 	require.NoError(suite.T(), err)
 
 	// Verify file was created
@@ -443,6 +1214,7 @@ func (suite *ExtractorTestSuite) TestSaveMessages() {
 	err = json.Unmarshal(data, &outputJSON)
 	require.NoError(suite.T(), err)
 
+	assert.Equal(suite.T(), "en", outputJSON.Language)
 	assert.Len(suite.T(), outputJSON.Messages, 2)
 	assert.Equal(suite.T(), "Hello", outputJSON.Messages[0].ID)
 	assert.Equal(suite.T(), "World", outputJSON.Messages[1].ID)
@@ -451,9 +1223,9 @@ func (suite *ExtractorTestSuite) TestSaveMessages() {
 // TestSaveMessagesEmptyOutput tests saveMessages with empty output path
 func (suite *ExtractorTestSuite) TestSaveMessagesEmptyOutput() {
 	messages := []*Message{{ID: "Hello", Positions: []string{"file1.html:1:1"}}}
-	extractor := NewExtractor("", "", "test", "test.go")
+	extractor := NewExtractor(WithPkg("test"), WithGoFile("test.go"))
 
-	err := extractor.saveMessages(messages)
+	err := extractor.saveMessages("", messages)
 	assert.NoError(suite.T(), err)
 }
 
@@ -465,9 +1237,9 @@ func (suite *ExtractorTestSuite) TestSaveGoFile() {
 	}
 
 	goFile := filepath.Join(suite.tempDir, "gotext_stub.go")
-	extractor := NewExtractor("", "", "testpkg", goFile)
+	extractor := NewExtractor(WithPkg("testpkg"), WithGoFile(goFile))
 
-	err := extractor.saveGoFile(messages)
+	err := extractor.saveGoFile("", messages)
 	require.NoError(suite.T(), err)
 
 	// Verify file was created
@@ -512,6 +1284,22 @@ func (suite *ExtractorTestSuite) TestBuildSyntheticGo() {
 			},
 			expected: []string{"package mypkg", "_ = p.Sprintf(\"Hello\")", "_ = p.Sprintf(\"World\")"},
 		},
+		{
+			name: "html message",
+			pkg:  "main",
+			messages: []*Message{
+				{ID: "<b>Bold</b>", Positions: []string{"file.html:1:1"}, HTML: true},
+			},
+			expected: []string{"\t// HTML markup is allowed in this message.\n\t_ = p.Sprintf(\"<b>Bold</b>\")"},
+		},
+		{
+			name: "keyed message",
+			pkg:  "main",
+			messages: []*Message{
+				{ID: "checkout.pay", Message: "Pay now", Positions: []string{"file.html:1:1"}},
+			},
+			expected: []string{"_ = p.Sprintf(message.Key(\"checkout.pay\", \"Pay now\"))"},
+		},
 		{
 			name:     "no messages",
 			pkg:      "empty",
@@ -522,44 +1310,67 @@ func (suite *ExtractorTestSuite) TestBuildSyntheticGo() {
 
 	for _, tt := range tests {
 		suite.Run(tt.name, func() {
-			extractor := NewExtractor("", "", tt.pkg, "test.go")
-			result, err := extractor.buildSyntheticGo(tt.messages)
+			extractor := NewExtractor(WithPkg(tt.pkg), WithGoFile("test.go"))
+			result, err := extractor.buildSyntheticGo("", tt.messages)
 			require.NoError(suite.T(), err)
 
 			content := string(result)
 			for _, expected := range tt.expected {
 				assert.Contains(suite.T(), content, expected)
 			}
+
+			formatted, err := format.Source(result)
+			require.NoError(suite.T(), err)
+			assert.Equal(suite.T(), string(formatted), content)
 		})
 	}
 }
 
+// TestSourceLanguage tests that the source language is recorded in the
+// messages JSON and used by the synthetic Go file
+func (suite *ExtractorTestSuite) TestSourceLanguage() {
+	messages := []*Message{{ID: "Ciao", Positions: []string{"file.html:1:1"}}}
+	extractor := NewExtractor(WithPkg("main"), WithSourceLanguage(language.MustParse("it-CH")))
+
+	goCode, err := extractor.buildSyntheticGo("", messages)
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(goCode), "p := message.NewPrinter(language.MustParse(\"it-CH\"))")
+
+	raw, err := extractor.messagesJSON("", messages)
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(raw), "\"language\": \"it-CH\"")
+
+	goCode, err = NewExtractor().buildSyntheticGo("", messages)
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(goCode), "p := message.NewPrinter(language.English)")
+}
+
 // TestExtractError tests error handling in extract method
 func (suite *ExtractorTestSuite) TestExtractError() {
 	// Create a directory that doesn't exist
-	extractor := NewExtractor("/nonexistent/directory", "", "test", "test.go", ".html")
+	extractor := NewExtractor(WithDir("/nonexistent/directory"), WithPkg("test"), WithGoFile("test.go"), WithExts(".html"))
 
-	messages, err := extractor.extract()
+	res, err := extractor.ExtractMessages()
 	assert.Error(suite.T(), err)
-	assert.Nil(suite.T(), messages)
+	assert.Nil(suite.T(), res)
 }
 
 // TestSaveMessagesError tests error handling in saveMessages
 func (suite *ExtractorTestSuite) TestSaveMessagesError() {
 	// Try to write to a directory that doesn't exist
 	messages := []*Message{{ID: "Hello", Positions: []string{"file.html:1:1"}}}
-	extractor := NewExtractor("", "/nonexistent/path/messages.json", "test", "test.go")
+	extractor := NewExtractor(WithOut("/nonexistent/path/messages.json"), WithPkg("test"), WithGoFile("test.go"))
 
-	err := extractor.saveMessages(messages)
+	err := extractor.saveMessages("", messages)
 	assert.Error(suite.T(), err)
 }
 
 // TestSaveGoFileError tests error handling in saveGoFile
 func (suite *ExtractorTestSuite) TestSaveGoFileError() {
 	messages := []*Message{{ID: "Hello", Positions: []string{"file.html:1:1"}}}
-	extractor := NewExtractor("", "/nonexistent/path/gotext.go", "test", "/nonexistent/path/gotext.go")
+	extractor := NewExtractor(WithOut("/nonexistent/path/gotext.go"), WithPkg("test"), WithGoFile("/nonexistent/path/gotext.go"))
 
-	err := extractor.saveGoFile(messages)
+	err := extractor.saveGoFile("", messages)
 	assert.Error(suite.T(), err)
 }
 
@@ -582,7 +1393,7 @@ func (suite *ExtractorTestSuite) TestExtractFullWorkflow() {
 	// Configure extractor
 	jsonOutput := filepath.Join(suite.tempDir, "messages.json")
 	goOutput := filepath.Join(suite.tempDir, "gotext_stub.go")
-	extractor := NewExtractor(suite.tempDir, jsonOutput, "testpkg", goOutput, ".html")
+	extractor := NewExtractor(WithDir(suite.tempDir), WithOut(jsonOutput), WithPkg("testpkg"), WithGoFile(goOutput), WithExts(".html"))
 
 	// Run full extraction
 	err = extractor.Extract()
@@ -636,7 +1447,7 @@ func (suite *ExtractorTestSuite) TestExtractWithNoOutputPaths() {
 	require.NoError(suite.T(), err)
 
 	// Configure extractor with empty output paths
-	extractor := NewExtractor(suite.tempDir, "", "testpkg", filepath.Join(suite.tempDir, "gotext.go"), ".html")
+	extractor := NewExtractor(WithDir(suite.tempDir), WithPkg("testpkg"), WithGoFile(filepath.Join(suite.tempDir, "gotext.go")), WithExts(".html"))
 
 	// This should not fail even with empty JSON output
 	err = extractor.Extract()
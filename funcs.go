@@ -0,0 +1,113 @@
+package i18n
+
+import (
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/message"
+)
+
+// Func describes a translation template function: its name and the position
+// of the message key among its arguments. The language is the first of the
+// remaining arguments and everything after it is passed to the message.
+type Func struct {
+	Name string
+	Arg  int  // position of the message key
+	HTML bool // translate with Thtml
+}
+
+// DefaultFuncs are the translation functions of FuncMap.
+var DefaultFuncs = []Func{
+	{Name: "i18n", Arg: 1},
+	{Name: "T", Arg: 1},
+	{Name: "t", Arg: 1},
+	{Name: "Thtml", Arg: 1, HTML: true},
+}
+
+// ParseFunc parses a function spec in the form name[:position][:html],
+// e.g. "tr", "trp:0" or "trh:1:html". The key position defaults to 1.
+func ParseFunc(s string) (Func, error) {
+	parts := strings.Split(s, ":")
+	f := Func{Name: parts[0], Arg: 1}
+
+	if f.Name == "" {
+		return Func{}, fmt.Errorf("invalid function %q: empty name", s)
+	}
+
+	if len(parts) > 1 && parts[1] != "" {
+		arg, err := strconv.Atoi(parts[1])
+		if err != nil || arg < 0 {
+			return Func{}, fmt.Errorf("invalid function %q: bad key position", s)
+		}
+		f.Arg = arg
+	}
+
+	if len(parts) > 2 {
+		if parts[2] != "html" || len(parts) > 3 {
+			return Func{}, fmt.Errorf("invalid function %q", s)
+		}
+		f.HTML = true
+	}
+
+	return f, nil
+}
+
+// ParseFuncs parses every spec with ParseFunc.
+func ParseFuncs(specs []string) ([]Func, error) {
+	funcs := make([]Func, 0, len(specs))
+	for _, spec := range specs {
+		f, err := ParseFunc(spec)
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, f)
+	}
+	return funcs, nil
+}
+
+// NewFuncMap returns a template.FuncMap with the "L" language parser, the
+// "Key" message reference of Key and the given translation functions.
+func NewFuncMap(funcs ...Func) template.FuncMap {
+	return std.FuncMap(funcs...)
+}
+
+func (f Func) fn(v view) any {
+	switch {
+	case f.Arg == 1 && f.HTML:
+		return v.transHTML
+	case f.Arg == 1:
+		return v.trans
+	case f.HTML:
+		return func(a ...any) (template.HTML, error) {
+			lang, key, rest, err := f.split(a)
+			if err != nil {
+				return "", err
+			}
+			return v.transHTML(lang, key, rest...), nil
+		}
+	default:
+		return func(a ...any) (string, error) {
+			lang, key, rest, err := f.split(a)
+			if err != nil {
+				return "", err
+			}
+			return v.trans(lang, key, rest...), nil
+		}
+	}
+}
+
+// split picks the key at position Arg and the language from the remaining
+// arguments.
+func (f Func) split(a []any) (lang any, key message.Reference, rest []any, err error) {
+	if len(a) < 2 || f.Arg >= len(a) {
+		return nil, nil, nil, fmt.Errorf("%s: expected language and key at position %d, got %d arguments", f.Name, f.Arg, len(a))
+	}
+
+	rest = make([]any, 0, len(a)-1)
+	rest = append(rest, a[:f.Arg]...)
+	rest = append(rest, a[f.Arg+1:]...)
+
+	return rest[0], a[f.Arg], rest[1:], nil
+}
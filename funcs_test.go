@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFunc(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected Func
+		err      bool
+	}{
+		{spec: "tr", expected: Func{Name: "tr", Arg: 1}},
+		{spec: "trp:0", expected: Func{Name: "trp", Arg: 0}},
+		{spec: "tr:2", expected: Func{Name: "tr", Arg: 2}},
+		{spec: "trh:1:html", expected: Func{Name: "trh", Arg: 1, HTML: true}},
+		{spec: "trh::html", expected: Func{Name: "trh", Arg: 1, HTML: true}},
+		{spec: "", err: true},
+		{spec: ":1", err: true},
+		{spec: "tr:x", err: true},
+		{spec: "tr:-1", err: true},
+		{spec: "tr:1:xml", err: true},
+		{spec: "tr:1:html:x", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			f, err := ParseFunc(tt.spec)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, f)
+		})
+	}
+}
+
+func TestParseFuncs(t *testing.T) {
+	funcs, err := ParseFuncs([]string{"i18n", "T", "t", "Thtml:1:html"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultFuncs, funcs)
+
+	_, err = ParseFuncs([]string{"tr", "tr:x"})
+	assert.Error(t, err)
+}
+
+func TestNewFuncMap(t *testing.T) {
+	fm := NewFuncMap(
+		Func{Name: "tr", Arg: 1},
+		Func{Name: "trp", Arg: 0},
+		Func{Name: "trn", Arg: 2},
+		Func{Name: "trh", Arg: 0, HTML: true},
+	)
+
+	assert.Contains(t, fm, "L")
+	assert.Contains(t, fm, "Key")
+	assert.Len(t, fm, 6)
+
+	tmpl := template.Must(template.New("test").Delims("[[", "]]").Funcs(fm).Parse(
+		`[[ tr .Lang "Hello %s" .Name ]]|[[ trp "Hello %s" .Lang .Name ]]|[[ trn .Lang .Name "Hello %s" ]]|[[ trh "<b>%s</b>" .Lang .Name ]]`,
+	))
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, map[string]any{"Lang": "en", "Name": "<Bob>"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello &lt;Bob&gt;|Hello &lt;Bob&gt;|Hello &lt;Bob&gt;|<b>&lt;Bob&gt;</b>", buf.String())
+}
+
+func TestFuncSplit(t *testing.T) {
+	f := Func{Name: "trp", Arg: 0}
+
+	lang, key, rest, err := f.split([]any{"Hello %s %s", "en", "a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "en", lang)
+	assert.Equal(t, "Hello %s %s", key)
+	assert.Equal(t, []any{"a", "b"}, rest)
+
+	_, _, _, err = f.split([]any{"Hello"})
+	assert.Error(t, err)
+
+	_, _, _, err = Func{Name: "tr", Arg: 3}.split([]any{"en", "Hello"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+// reArgIndex matches the explicit argument index of a fmt verb, e.g. [1] in %[1]d.
+var reArgIndex = regexp.MustCompile(`\[\d+\]`)
+
+var catalogTemplate = template.Must(template.New("catalog").Parse(`// Code generated by i18n generate. DO NOT EDIT.
+
+package {{ .Pkg }}
+
+import (
+{{- if .Plural }}
+	"golang.org/x/text/feature/plural"
+{{- end }}
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+
+	"github.com/gowool/i18n"
+)
+
+func init() {
+	set := func(lang, key string, msg catalog.Message) {
+		if err := i18n.SetMessage(language.MustParse(lang), key, msg); err != nil {
+			panic(err)
+		}
+	}
+{{- range .Languages }}
+{{- $lang := .Lang }}
+
+	// {{ $lang }}
+{{- range .Messages }}
+	set({{ printf "%q" $lang }}, {{ printf "%q" .Key }}, {{ .Msg }})
+{{- end }}
+{{- end }}
+}
+`))
+
+type (
+	catalogData struct {
+		Pkg       string
+		Plural    bool
+		Languages []catalogLanguage
+	}
+	catalogLanguage struct {
+		Lang     string
+		Messages []catalogMessage
+	}
+	catalogMessage struct {
+		Key string
+		Msg string // Go expression of the catalog.Message
+	}
+	gotextPlaceholder struct {
+		ID     string `json:"id"`
+		String string `json:"string"`
+		ArgNum int    `json:"argNum"`
+	}
+)
+
+// GenerateCatalog returns the source of a Go file in package pkg that adds
+// the translations of files to the active catalog with SetMessage on init.
+// Files of the same language are merged; untranslated messages are left out.
+func GenerateCatalog(pkg string, files ...*TranslationFile) ([]byte, error) {
+	data := catalogData{Pkg: sanitizePkgName(pkg)}
+
+	byLang := make(map[string]map[string]string)
+	for _, f := range files {
+		tag, err := language.Parse(f.Language)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", f.Language, err)
+		}
+
+		lang := tag.String()
+		if byLang[lang] == nil {
+			byLang[lang] = make(map[string]string)
+		}
+
+		for _, m := range f.Messages {
+			msg, plural, err := catalogMessageExpr(m)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %q: %w", lang, m.ID, err)
+			}
+			if msg == "" {
+				continue
+			}
+
+			key := m.Key
+			if key == "" {
+				key = m.ID
+			}
+			byLang[lang][key] = msg
+			data.Plural = data.Plural || plural
+		}
+	}
+
+	for _, lang := range slices.Sorted(maps.Keys(byLang)) {
+		l := catalogLanguage{Lang: lang}
+		for _, key := range slices.Sorted(maps.Keys(byLang[lang])) {
+			l.Messages = append(l.Messages, catalogMessage{Key: key, Msg: byLang[lang][key]})
+		}
+		data.Languages = append(data.Languages, l)
+	}
+
+	var buf bytes.Buffer
+	if err := catalogTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// catalogMessageExpr returns the Go expression of the translation of m and
+// whether it is a plural message, or "" if m is not translated.
+func catalogMessageExpr(m *Translation) (string, bool, error) {
+	t, arg, verb, err := formatText(m)
+	if err != nil {
+		return "", false, err
+	}
+
+	if t.Cases == nil {
+		if t.Msg == "" {
+			return "", false, nil
+		}
+		return fmt.Sprintf("catalog.String(%q)", t.Msg), false, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "plural.Selectf(%d, %q", arg, verb)
+	for _, c := range pluralCases(t.Cases) {
+		fmt.Fprintf(&b, ", %q, %q", c, t.Cases[c])
+	}
+	b.WriteString(")")
+
+	return b.String(), true, nil
+}
+
+// translationMessage is like catalogMessageExpr, returning the message itself
+// or nil if m is not translated.
+func translationMessage(m *Translation) (catalog.Message, error) {
+	t, arg, verb, err := formatText(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Cases == nil {
+		if t.Msg == "" {
+			return nil, nil
+		}
+		return catalog.String(t.Msg), nil
+	}
+
+	cases := make([]any, 0, 2*len(t.Cases))
+	for _, c := range pluralCases(t.Cases) {
+		cases = append(cases, c, t.Cases[c])
+	}
+	return plural.Selectf(arg, verb, cases...), nil
+}
+
+// formatText returns the translation of m with gotext placeholders such as
+// {Count} replaced by their fmt verbs, and for plural messages the number
+// and verb of the selecting argument.
+func formatText(m *Translation) (t Text, arg int, verb string, err error) {
+	placeholders, err := m.placeholders()
+	if err != nil {
+		return Text{}, 0, "", err
+	}
+
+	t = m.Translation.replace(formatReplacer(placeholders))
+	if t.Cases == nil {
+		return t, 0, "", nil
+	}
+
+	arg = 1
+	for _, p := range placeholders {
+		if p.ID == t.Arg {
+			arg, verb = p.ArgNum, reArgIndex.ReplaceAllString(p.String, "")
+		}
+	}
+	return t, arg, verb, nil
+}
+
+// formatSource is like formatText for the source text of m.
+func formatSource(m *Translation) (Text, error) {
+	placeholders, err := m.placeholders()
+	if err != nil {
+		return Text{}, err
+	}
+	return m.Message.replace(formatReplacer(placeholders)), nil
+}
+
+// formatReplacer returns a function replacing gotext placeholders by their
+// fmt verbs.
+func formatReplacer(placeholders []gotextPlaceholder) func(string) string {
+	replacements := make([]string, 0, 2*len(placeholders))
+	for _, p := range placeholders {
+		replacements = append(replacements, "{"+p.ID+"}", p.String)
+	}
+	return strings.NewReplacer(replacements...).Replace
+}
+
+// placeholders decodes the gotext placeholders of m.
+func (m *Translation) placeholders() ([]gotextPlaceholder, error) {
+	var placeholders []gotextPlaceholder
+	if len(m.Placeholders) > 0 {
+		if err := json.Unmarshal(m.Placeholders, &placeholders); err != nil {
+			return nil, fmt.Errorf("placeholders: %w", err)
+		}
+	}
+	return placeholders, nil
+}
+
+// pluralCases orders plural cases as plural.Selectf expects: explicit values
+// like "=0" first, then the CLDR categories, "other" last.
+func pluralCases(cases map[string]string) []string {
+	order := map[string]int{"zero": 1, "one": 2, "two": 3, "few": 4, "many": 5, "other": 6}
+	return slices.SortedFunc(maps.Keys(cases), func(a, b string) int {
+		if c := order[a] - order[b]; c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+}
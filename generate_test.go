@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCatalog(t *testing.T) {
+	files := []*TranslationFile{
+		{
+			Language: "de",
+			Messages: []*Translation{
+				{ID: "Hello {Arg_1}", Key: "Hello %s", Translation: Text{Msg: "Hallo {Arg_1}"}, Placeholders: json.RawMessage(`[{"id": "Arg_1", "string": "%[1]s", "argNum": 1}]`)},
+				{ID: "checkout.pay", Message: Text{Msg: "Pay {amount}"}, Translation: Text{Msg: "Zahle {amount}"}},
+				{ID: "Untranslated"},
+			},
+		},
+		{
+			Language: "fr",
+			Messages: []*Translation{
+				{ID: "{Count} items", Key: "%d items", Translation: Text{Arg: "Count", Cases: map[string]string{
+					"other": "{Count} articles",
+					"=0":    "aucun article",
+					"one":   "un article",
+				}}, Placeholders: json.RawMessage(`[{"id": "Count", "string": "%[1]d", "argNum": 1}]`)},
+			},
+		},
+		{
+			Language: "de",
+			Messages: []*Translation{{ID: "Bye", Translation: Text{Msg: "Tschüss"}}},
+		},
+	}
+
+	src, err := GenerateCatalog("locales", files...)
+	require.NoError(t, err)
+
+	assert.Equal(t, `// Code generated by i18n generate. DO NOT EDIT.
+
+package locales
+
+import (
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+
+	"github.com/gowool/i18n"
+)
+
+func init() {
+	set := func(lang, key string, msg catalog.Message) {
+		if err := i18n.SetMessage(language.MustParse(lang), key, msg); err != nil {
+			panic(err)
+		}
+	}
+
+	// de
+	set("de", "Bye", catalog.String("Tschüss"))
+	set("de", "Hello %s", catalog.String("Hallo %[1]s"))
+	set("de", "checkout.pay", catalog.String("Zahle {amount}"))
+
+	// fr
+	set("fr", "%d items", plural.Selectf(1, "%d", "=0", "aucun article", "one", "un article", "other", "%[1]d articles"))
+}
+`, string(src))
+
+	t.Run("Without plurals", func(t *testing.T) {
+		src, err := GenerateCatalog("main", files[2])
+		require.NoError(t, err)
+		assert.NotContains(t, string(src), "feature/plural")
+	})
+
+	t.Run("Invalid language", func(t *testing.T) {
+		_, err := GenerateCatalog("main", &TranslationFile{Language: "not a tag"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid placeholders", func(t *testing.T) {
+		_, err := GenerateCatalog("main", &TranslationFile{Language: "de", Messages: []*Translation{
+			{ID: "x", Translation: Text{Msg: "x"}, Placeholders: json.RawMessage(`{}`)},
+		}})
+		assert.ErrorContains(t, err, `de: "x": placeholders`)
+	})
+}
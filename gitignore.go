@@ -0,0 +1,158 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// gitignore matches paths against the rules of the .gitignore files found
+// while walking a directory tree.
+type gitignore struct {
+	rules []gitignoreRule
+}
+
+type gitignoreRule struct {
+	base    string // slash separated directory of the .gitignore, "" for the root
+	above   string // the root relative to the .gitignore, for .gitignore files above it
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// load reads the .gitignore of dir in fsys, if any. rel is dir relative to
+// the walk root.
+func (g *gitignore) load(fsys fs.FS, dir, rel string) error {
+	b, err := fs.ReadFile(fsys, path.Join(dir, ".gitignore"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	g.parse(b, rel)
+	return nil
+}
+
+// loadParents reads the .gitignore files of the directories above root in
+// fsys, up to the root of its git repository, the first one holding .git.
+// Outside of a repository none are read.
+func (g *gitignore) loadParents(fsys fs.FS, root string) error {
+	var dirs []string // innermost first
+	for dir := root; ; {
+		if _, err := fs.Stat(fsys, path.Join(dir, ".git")); err == nil {
+			break
+		}
+		if dir == "." {
+			return nil
+		}
+		dir = path.Dir(dir)
+		dirs = append(dirs, dir)
+	}
+
+	for _, dir := range slices.Backward(dirs) {
+		b, err := fs.ReadFile(fsys, path.Join(dir, ".gitignore"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		n := len(g.rules)
+		g.parse(b, "")
+		for i := range g.rules[n:] {
+			g.rules[n+i].above = relPath(dir, root)
+		}
+	}
+	return nil
+}
+
+func (g *gitignore) parse(content []byte, base string) {
+	if base == "." {
+		base = ""
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		rule := gitignoreRule{base: base}
+
+		switch {
+		case line[0] == '!':
+			rule.negate = true
+			line = line[1:]
+		case strings.HasPrefix(line, `\#`), strings.HasPrefix(line, `\!`):
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+
+		// a pattern with a slash is relative to the .gitignore directory,
+		// one without matches at any level below it
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+
+		if line == "" {
+			continue
+		}
+
+		rule.pattern = line
+		g.rules = append(g.rules, rule)
+	}
+}
+
+// ignored reports whether the slash separated path rel is ignored. The last
+// matching rule wins, so negated rules re-include earlier matches.
+func (g *gitignore) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		name := rel
+		if rule.above != "" {
+			name = rule.above + "/" + rel
+		}
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			name = strings.TrimPrefix(rel, rule.base+"/")
+		}
+
+		if ok, _ := doublestar.Match(rule.pattern, name); ok {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matchAny reports whether the slash separated path rel matches any of the
+// doublestar patterns.
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := doublestar.Match(path.Clean(pattern), rel); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitignore(t *testing.T) {
+	var g gitignore
+	g.parse([]byte(`
+# comment
+node_modules/
+/build
+*.gen.html
+!keep.gen.html
+docs/**/*.tmpl
+\#literal
+`), ".")
+	g.parse([]byte("local.html\n"), "themes/admin")
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"node_modules", true, true},
+		{"themes/node_modules", true, true},
+		{"node_modules", false, false},
+		{"build", true, true},
+		{"themes/build", true, false},
+		{"page.gen.html", false, true},
+		{"themes/page.gen.html", false, true},
+		{"keep.gen.html", false, false},
+		{"docs/a/b/page.tmpl", false, true},
+		{"themes/docs/page.tmpl", false, false},
+		{"#literal", false, true},
+		{"themes/admin/local.html", false, true},
+		{"themes/admin/sub/local.html", false, true},
+		{"themes/shop/local.html", false, false},
+		{"page.html", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, g.ignored(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestGitignoreLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	var g gitignore
+	require.NoError(t, g.load(os.DirFS(dir), ".", "."))
+	assert.Empty(t, g.rules)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("vendor/\n"), 0644))
+	require.NoError(t, g.load(os.DirFS(dir), ".", "."))
+	assert.True(t, g.ignored("vendor", true))
+}
+
+func TestGitignoreLoadParents(t *testing.T) {
+	fsys := fstest.MapFS{
+		".git/HEAD":         {Data: []byte("ref: refs/heads/main\n")},
+		".gitignore":        {Data: []byte("node_modules/\n/build\nweb/templates/drafts/\n")},
+		"web/.gitignore":    {Data: []byte("*.gen.html\n")},
+		"web/templates/x":   {Data: []byte("")},
+		"other/.gitignore":  {Data: []byte("*.html\n")},
+		"other/templates/x": {Data: []byte("")},
+	}
+
+	var g gitignore
+	require.NoError(t, g.loadParents(fsys, "web/templates"))
+	assert.True(t, g.ignored("node_modules", true))
+	assert.True(t, g.ignored("shop/node_modules", true))
+	assert.True(t, g.ignored("page.gen.html", false))
+	assert.True(t, g.ignored("drafts", true))
+	assert.False(t, g.ignored("build", true))
+	assert.False(t, g.ignored("page.html", false))
+
+	t.Run("Outside of a repository", func(t *testing.T) {
+		delete(fsys, ".git/HEAD")
+
+		var g gitignore
+		require.NoError(t, g.loadParents(fsys, "web/templates"))
+		assert.Empty(t, g.rules)
+	})
+}
+
+func TestMatchAny(t *testing.T) {
+	patterns := []string{"**/node_modules", "./admin/**/*.html"}
+
+	assert.True(t, matchAny(patterns, "node_modules"))
+	assert.True(t, matchAny(patterns, "a/b/node_modules"))
+	assert.True(t, matchAny(patterns, "admin/x/page.html"))
+	assert.False(t, matchAny(patterns, "shop/page.html"))
+	assert.False(t, matchAny(nil, "page.html"))
+}
@@ -3,71 +3,231 @@ package i18n
 import (
 	"fmt"
 	"html/template"
+	"reflect"
+	"regexp"
 	"sync"
 	"sync/atomic"
 
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
 )
 
-var FuncMap = template.FuncMap{
-	"L":    language.MustParse,
-	"T":    trans,
-	"t":    trans,
-	"i18n": trans,
-}
+var FuncMap = NewFuncMap(DefaultFuncs...)
+
+// reKey matches dotted message keys like "checkout.button.pay".
+var reKey = regexp.MustCompile(`^[A-Za-z_][\w-]*(\.[\w-]+)+$`)
 
 var (
-	fallback atomic.Value
+	fallback atomic.Pointer[language.Tag]
 	printers sync.Map
+	source   atomic.Pointer[sourceLanguage]
 )
 
+// sourceLanguage is the language the source texts are written in and a
+// printer without translations, which prints messages as written.
+type sourceLanguage struct {
+	tag     language.Tag
+	printer catalogPrinter
+}
+
+func init() {
+	SetSourceLanguage(language.English)
+}
+
+// Fallback returns the language set with SetFallback, or the source
+// language.
 func Fallback() language.Tag {
 	if tag := fallback.Load(); tag != nil {
-		return tag.(language.Tag)
+		return *tag
 	}
-	return language.English
+	return SourceLanguage()
 }
 
 func SetFallback(tag language.Tag) {
-	fallback.Store(tag)
+	fallback.Store(&tag)
+}
+
+// SourceLanguage returns the language the messages are written in, English
+// by default.
+func SourceLanguage() language.Tag {
+	return source.Load().tag
+}
+
+// SetSourceLanguage sets the language the messages are written in. Messages
+// the active catalog lacks in that language, or in a regional variant of it,
+// are printed as written, and it is the default Fallback.
+func SetSourceLanguage(tag language.Tag) {
+	source.Store(&sourceLanguage{tag: tag, printer: newPrinter(tag, catalog.NewBuilder())})
+}
+
+// isSource reports whether tag is the source language or a variant of it,
+// such as en-US for en.
+func isSource(tag language.Tag) bool {
+	src := SourceLanguage()
+	for ; ; tag = tag.Parent() {
+		if tag == src {
+			return true
+		}
+		if tag.IsRoot() {
+			return false
+		}
+	}
 }
 
+// Printer returns the printer of tag: the one registered with SetPrinter or,
+// if supported languages are set, the printer of the closest of them. Tags of
+// the source language get the printer of the source language even when it is
+// not supported, so they are never translated to another language.
 func Printer(tag language.Tag) *message.Printer {
+	return printerOf(tag).Printer
+}
+
+// printerOf is like Printer, returning the catalog of the printer too.
+func printerOf(tag language.Tag) catalogPrinter {
 	if v, ok := printers.Load(tag); ok {
-		return v.(*message.Printer)
+		return v.(catalogPrinter)
 	}
 
-	printer := message.NewPrinter(tag)
-	printers.Store(tag, printer)
+	set := supported.Load()
+	if set == nil {
+		return unmatched.get(tag)
+	}
 
-	return printer
+	tag = set.resolve(tag)
+	if v, ok := printers.Load(tag); ok {
+		return v.(catalogPrinter)
+	}
+
+	v, _ := printers.LoadOrStore(tag, newPrinter(tag, message.DefaultCatalog))
+	return v.(catalogPrinter)
 }
 
+// SetPrinter registers the printer of tag. Its catalog is unknown, so
+// messages with named placeholders are formatted by it like Printf formats,
+// where a literal % must be written as %%; SetPrinterCatalog has no such
+// limitation.
 func SetPrinter(tag language.Tag, printer *message.Printer) {
-	printers.Store(tag, printer)
+	printers.Store(tag, catalogPrinter{Printer: printer})
 }
 
+// SetPrinterCatalog registers a printer of tag formatting the messages of
+// cat, as if set with SetPrinter.
+func SetPrinterCatalog(tag language.Tag, cat catalog.Catalog) {
+	printers.Store(tag, newPrinter(tag, cat))
+}
+
+// T translates key for tag. Keys with named placeholders ("Hello {name}")
+// take their arguments either as name/value pairs or as a single map; any
+// other key is a Printf format taking positional arguments. A message with a
+// stable key instead of its source text is referenced with Key.
 func T(tag language.Tag, key message.Reference, a ...any) string {
-	return Printer(tag).Sprintf(key, a...)
+	return std.T(tag, key, a...)
+}
+
+// Thtml is like T, but the translated format is trusted HTML: markup in the
+// message is kept as is, while every interpolated argument is HTML-escaped
+// unless it is already a template.HTML value.
+func Thtml(tag language.Tag, key message.Reference, a ...any) template.HTML {
+	return std.Thtml(tag, key, a...)
+}
+
+// messageKey is a message referenced by a stable key, see Key.
+type messageKey struct {
+	id     string
+	source string
+}
+
+// Key returns a reference to the message of a stable key such as
+// "checkout.pay", whose source text is printed until the key is translated:
+// T(tag, Key("checkout.pay", "Pay now")). Templates have it as the function
+// Key: {{ T .Lang (Key "checkout.pay" "Pay now") }}.
+func Key(id, source string) message.Reference {
+	return messageKey{id: id, source: source}
+}
+
+// reference returns the reference printers format for key, and the ID and
+// source text of key.
+func reference(key message.Reference) (ref message.Reference, id, source string) {
+	switch key := key.(type) {
+	case string:
+		return key, key, key
+	case messageKey:
+		return message.Key(key.id, key.source), key.id, key.source
+	}
+	return key, "", ""
+}
+
+// sprintf formats the message key, whose ID is id and source text is source,
+// with p. Messages with named placeholders are not Printf formats, so their
+// text is expanded without interpreting a literal % as a verb.
+func sprintf(p catalogPrinter, key message.Reference, id, source string, a []any) string {
+	if rePlaceholder.MatchString(source) {
+		if named, ok := namedArgs(a); ok {
+			return expandPlaceholders(p.text(key, id, source), named, p.Printer)
+		}
+	}
+	return p.Sprintf(key, a...)
 }
 
-func trans(lang any, key message.Reference, a ...any) string {
-	var tag language.Tag
+func langTag(lang any) language.Tag {
 	switch lang := lang.(type) {
 	case language.Tag:
-		tag = lang
+		return lang
 	case *language.Tag:
-		tag = *lang
+		return *lang
 	case string:
-		tag = language.MustParse(lang)
+		return language.MustParse(lang)
 	case *string:
-		tag = language.MustParse(*lang)
+		return language.MustParse(*lang)
 	case fmt.Stringer:
-		tag = language.MustParse(lang.String())
+		return language.MustParse(lang.String())
 	default:
 		panic("invalid language tag")
 	}
+}
+
+func escapeArgs(a []any) []any {
+	escaped := make([]any, len(a))
+	for i, arg := range a {
+		escaped[i] = escapeArg(arg)
+	}
+	return escaped
+}
 
-	return T(tag, key, a...)
+func escapeArg(arg any) any {
+	switch arg := arg.(type) {
+	case nil, template.HTML:
+		return arg
+	case string:
+		return template.HTMLEscapeString(arg)
+	case fmt.Stringer:
+		return template.HTMLEscapeString(arg.String())
+	case error:
+		return template.HTMLEscapeString(arg.Error())
+	case map[string]any:
+		escaped := make(map[string]any, len(arg))
+		for k, v := range arg {
+			escaped[k] = escapeArg(v)
+		}
+		return escaped
+	case map[string]string:
+		escaped := make(map[string]string, len(arg))
+		for k, v := range arg {
+			escaped[k] = template.HTMLEscapeString(v)
+		}
+		return escaped
+	}
+
+	// numbers and booleans keep their type so that verbs like %d
+	// still get locale-aware formatting
+	switch reflect.ValueOf(arg).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return arg
+	default:
+		return template.HTMLEscapeString(fmt.Sprint(arg))
+	}
 }
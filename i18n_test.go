@@ -1,12 +1,16 @@
 package i18n
 
 import (
+	"bytes"
+	"html/template"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
 )
 
 func TestFallback(t *testing.T) {
@@ -48,6 +52,69 @@ func TestFallback(t *testing.T) {
 	})
 }
 
+func TestSourceLanguage(t *testing.T) {
+	tag := language.MustParse("fur")
+	regional := language.MustParse("fur-IT")
+	require.NoError(t, Set(tag, "test.source.hello", "Mandi"))
+	require.NoError(t, Set(language.German, "test.source.hello", "Hallo"))
+
+	saved := fallback.Load()
+	t.Cleanup(func() {
+		SetSourceLanguage(language.English)
+		fallback.Store(saved)
+	})
+	fallback.Store(nil)
+
+	assert.Equal(t, language.English, SourceLanguage())
+	assert.Equal(t, "Mandi", T(tag, Key("test.source.hello", "Hello")))
+
+	SetSourceLanguage(tag)
+	assert.Equal(t, tag, SourceLanguage())
+	assert.Equal(t, tag, Fallback())
+
+	t.Run("Messages of the catalog come first", func(t *testing.T) {
+		msg, ok := Lookup(tag, "test.source.hello")
+		assert.True(t, ok)
+		assert.Equal(t, msg, T(tag, Key("test.source.hello", "Hello")))
+		assert.Equal(t, "Mandi", T(regional, Key("test.source.hello", "Hello")))
+	})
+
+	t.Run("Missing messages are printed as written", func(t *testing.T) {
+		assert.Equal(t, "Goodbye", T(tag, Key("test.source.bye", "Goodbye")))
+		assert.Equal(t, "test.source.bye", T(regional, "test.source.bye"))
+		assert.Equal(t, "Hello Bob", T(regional, "Hello %s", "Bob"))
+	})
+
+	t.Run("Supported languages", func(t *testing.T) {
+		t.Cleanup(func() { SetLanguages() })
+
+		SetLanguages(language.German, tag)
+		assert.Same(t, Printer(tag), Printer(regional))
+		assert.Equal(t, "Mandi", T(regional, Key("test.source.hello", "Hello")))
+
+		SetLanguages(language.German)
+		assert.Same(t, Printer(tag), Printer(regional))
+		assert.Equal(t, "Mandi", T(regional, Key("test.source.hello", "Hello")))
+		assert.Equal(t, "Goodbye", T(regional, Key("test.source.bye", "Goodbye")))
+		assert.Equal(t, "Hallo", T(language.Italian, Key("test.source.hello", "Hello")))
+	})
+
+	SetFallback(language.German)
+	assert.Equal(t, language.German, Fallback())
+}
+
+func TestSourceLanguageCatalog(t *testing.T) {
+	require.NoError(t, Set(language.English, "test.source.greeting", "Hi there"))
+	require.NoError(t, LoadTranslations(fstest.MapFS{
+		"locales/en.yaml": {Data: []byte("test:\n  source:\n    pay: Pay now\n")},
+	}, "locales"))
+
+	assert.Equal(t, "Hi there", T(language.English, "test.source.greeting"))
+	assert.Equal(t, "Hi there", T(language.AmericanEnglish, "test.source.greeting"))
+	assert.Equal(t, "Pay now", T(language.English, "test.source.pay"))
+	assert.Equal(t, "Pay now", T(language.English, Key("test.source.pay", "Pay")))
+}
+
 func TestPrinter(t *testing.T) {
 	t.Run("Create and retrieve printer", func(t *testing.T) {
 		tag := language.Spanish
@@ -150,12 +217,97 @@ func TestT(t *testing.T) {
 	})
 }
 
+func TestTKeyed(t *testing.T) {
+	t.Run("Untranslated key prints source text", func(t *testing.T) {
+		assert.Equal(t, "Pay now", T(language.English, Key("checkout.pay", "Pay now")))
+	})
+
+	t.Run("Translated key", func(t *testing.T) {
+		tag := language.Finnish
+		cat := catalog.NewBuilder()
+		require.NoError(t, cat.SetString(tag, "checkout.pay", "Maksa nyt"))
+		require.NoError(t, cat.SetString(tag, "checkout.total", "Yhteensä %d €"))
+		require.NoError(t, cat.SetString(tag, "checkout.hello", "Hei {name}"))
+		SetPrinter(tag, message.NewPrinter(tag, message.Catalog(cat)))
+
+		assert.Equal(t, "Maksa nyt", T(tag, Key("checkout.pay", "Pay now")))
+		assert.Equal(t, "Yhteensä 5 €", T(tag, Key("checkout.total", "Total %d €"), 5))
+		assert.Equal(t, "Hei Bob", T(tag, Key("checkout.hello", "Hello {name}"), "name", "Bob"))
+	})
+
+	t.Run("Source text with arguments", func(t *testing.T) {
+		assert.Equal(t, "Total 1,200 €", T(language.English, Key("checkout.total", "Total %d €"), 1200))
+		assert.Equal(t, "Hello Bob", T(language.English, Key("greeting.hello", "Hello {name}"), "name", "Bob"))
+	})
+
+	t.Run("Key without source text", func(t *testing.T) {
+		assert.Equal(t, "checkout.pay", T(language.English, "checkout.pay"))
+	})
+
+	t.Run("Dotted IDs take positional arguments", func(t *testing.T) {
+		tag := language.MustParse("sc")
+		require.NoError(t, message.SetString(tag, "test.user.greeting", "Salude %s"))
+
+		assert.Equal(t, "Salude Bob", T(tag, "test.user.greeting", "Bob"))
+		assert.Equal(t, template.HTML("Salude &lt;Bob&gt;"), Thtml(tag, "test.user.greeting", "<Bob>"))
+	})
+
+	t.Run("Sentences are not keys", func(t *testing.T) {
+		assert.Equal(t, "Hello World", T(language.English, "Hello %s", "World"))
+		assert.Equal(t, "Done. World", T(language.English, "Done. %s", "World"))
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		tmpl := template.Must(template.New("test").Funcs(FuncMap).Parse(`{{ T "en" (Key "checkout.pay" "Pay now") }} {{ Thtml "en" (Key "checkout.terms" "Read the <a href=\"%s\">terms</a>") .URL }}`))
+
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.Execute(&buf, map[string]string{"URL": "/t?a<b"}))
+		assert.Equal(t, `Pay now Read the <a href="/t?a&lt;b">terms</a>`, buf.String())
+	})
+}
+
+func TestThtml(t *testing.T) {
+	t.Run("Markup in message is kept", func(t *testing.T) {
+		result := Thtml(language.English, `Read the <a href="%s">terms</a>`, "/terms")
+		assert.Equal(t, template.HTML(`Read the <a href="/terms">terms</a>`), result)
+	})
+
+	t.Run("String arguments are escaped", func(t *testing.T) {
+		result := Thtml(language.English, "<b>Hello %s</b>", `<script>alert("x")</script>`)
+		assert.Equal(t, template.HTML("<b>Hello &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</b>"), result)
+	})
+
+	t.Run("Trusted HTML arguments are not escaped", func(t *testing.T) {
+		result := Thtml(language.English, "<p>%s</p>", template.HTML("<em>safe</em>"))
+		assert.Equal(t, template.HTML("<p><em>safe</em></p>"), result)
+	})
+
+	t.Run("Numbers keep locale formatting", func(t *testing.T) {
+		result := Thtml(language.English, "<b>%d</b> items", 1200)
+		assert.Equal(t, template.HTML("<b>1,200</b> items"), result)
+	})
+
+	t.Run("Stringer and other values are escaped", func(t *testing.T) {
+		result := Thtml(language.English, "%s %v", &testStringer{"<x>"}, []string{"<y>"})
+		assert.Equal(t, template.HTML("&lt;x&gt; [&lt;y&gt;]"), result)
+	})
+
+	t.Run("Template escapes arguments but not markup", func(t *testing.T) {
+		tmpl := template.Must(template.New("test").Funcs(FuncMap).Parse(`{{ Thtml "en" "Read the <a href=\"%s\">terms</a>" .URL }}`))
+
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, map[string]string{"URL": `/terms?a="b"`})
+		require.NoError(t, err)
+		assert.Equal(t, `Read the <a href="/terms?a=&#34;b&#34;">terms</a>`, buf.String())
+	})
+}
+
 func TestTrans(t *testing.T) {
 	t.Run("Language.Tag input", func(t *testing.T) {
 		tag := language.German
 		key := "Test message"
 
-		result := trans(tag, key)
+		result := std.trans(tag, key)
 		assert.Equal(t, "Test message", result)
 	})
 
@@ -163,7 +315,7 @@ func TestTrans(t *testing.T) {
 		tag := language.Russian
 		key := "Test message"
 
-		result := trans(&tag, key)
+		result := std.trans(&tag, key)
 		assert.Equal(t, "Test message", result)
 	})
 
@@ -171,7 +323,7 @@ func TestTrans(t *testing.T) {
 		langStr := "zh-CN"
 		key := "Test message"
 
-		result := trans(langStr, key)
+		result := std.trans(langStr, key)
 		assert.Equal(t, "Test message", result)
 	})
 
@@ -179,7 +331,7 @@ func TestTrans(t *testing.T) {
 		langStr := "ja"
 		key := "Test message"
 
-		result := trans(&langStr, key)
+		result := std.trans(&langStr, key)
 		assert.Equal(t, "Test message", result)
 	})
 
@@ -187,7 +339,7 @@ func TestTrans(t *testing.T) {
 		stringer := &testStringer{"ar"}
 		key := "Test message"
 
-		result := trans(stringer, key)
+		result := std.trans(stringer, key)
 		assert.Equal(t, "Test message", result)
 	})
 
@@ -196,21 +348,21 @@ func TestTrans(t *testing.T) {
 		key := "Hello %s, you have %d messages"
 		args := []any{"Alice", 5}
 
-		result := trans(tag, key, args...)
+		result := std.trans(tag, key, args...)
 		assert.Equal(t, "Hello Alice, you have 5 messages", result)
 	})
 
 	t.Run("Invalid language type should panic", func(t *testing.T) {
 		assert.Panics(t, func() {
-			trans(123, "test message")
+			std.trans(123, "test message")
 		}, "Should panic with invalid language tag")
 
 		assert.Panics(t, func() {
-			trans(nil, "test message")
+			std.trans(nil, "test message")
 		}, "Should panic with nil language tag")
 
 		assert.Panics(t, func() {
-			trans([]string{"en"}, "test message")
+			std.trans([]string{"en"}, "test message")
 		}, "Should panic with slice language tag")
 	})
 }
@@ -221,6 +373,7 @@ func TestFuncMap(t *testing.T) {
 		assert.Contains(t, FuncMap, "T")
 		assert.Contains(t, FuncMap, "t")
 		assert.Contains(t, FuncMap, "i18n")
+		assert.Contains(t, FuncMap, "Thtml")
 	})
 
 	t.Run("L function parses language tags", func(t *testing.T) {
@@ -273,7 +426,7 @@ func TestIntegration(t *testing.T) {
 		result := T(tag, key, name)
 		assert.Equal(t, "Welcome User!", result)
 
-		templateResult := trans(tag, key, name)
+		templateResult := std.trans(tag, key, name)
 		assert.Equal(t, result, templateResult)
 	})
 
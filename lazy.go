@@ -0,0 +1,310 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// LocaleLoader returns the catalog of the messages of one language.
+type LocaleLoader func(tag language.Tag) (catalog.Catalog, error)
+
+// LocaleFS returns a LocaleLoader reading the translation files of a
+// language in dir of fsys. Files are assigned to languages by their paths,
+// as in de.yaml, messages.de.toml or de/messages.gotext.json, so only the
+// files of the loaded language are read.
+func LocaleFS(fsys fs.FS, dir string) LocaleLoader {
+	return func(tag language.Tag) (catalog.Catalog, error) {
+		files, err := readTranslationFS(fsys, dir, func(path string) bool {
+			return pathLanguage(path) == tag.String()
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		b := newKeyedCatalog()
+		return b, setTranslations(files, b.Set)
+	}
+}
+
+// Delays before a use retries a failed load, see LazyCatalog.SetRetryDelay.
+const (
+	defaultRetryDelay = time.Second
+	maxRetryDelay     = 5 * time.Minute
+)
+
+// LazyCatalog is a CatalogProvider loading the catalog of a language the
+// first time a message is translated to it, so only the languages actually
+// served are kept in memory. Concurrent first uses of a language wait for a
+// single load. A failed load is recorded, see Err, and retried by a use
+// only after a delay, see SetRetryDelay; meanwhile the language is served
+// without the catalog.
+type LazyCatalog struct {
+	tags       []language.Tag
+	matcher    language.Matcher
+	load       LocaleLoader
+	locales    map[language.Tag]*lazyLocale
+	retryDelay atomic.Int64 // of type time.Duration
+}
+
+// NewLazyCatalog returns a LazyCatalog of the languages tags, whose catalogs
+// are loaded by load. Requested tags are matched to the closest of them.
+func NewLazyCatalog(load LocaleLoader, tags ...language.Tag) *LazyCatalog {
+	c := &LazyCatalog{
+		tags:    slices.Clone(tags),
+		matcher: language.NewMatcher(tags),
+		load:    load,
+		locales: make(map[language.Tag]*lazyLocale, len(tags)),
+	}
+	for _, tag := range tags {
+		c.locales[tag] = &lazyLocale{}
+	}
+	c.retryDelay.Store(int64(defaultRetryDelay))
+	return c
+}
+
+// NewLazyCatalogFS returns a LazyCatalog of the translation files in dir of
+// fsys, see LocaleFS. Its languages are the ones named by the file paths;
+// nothing is read until a language is used.
+func NewLazyCatalogFS(fsys fs.FS, dir string) (*LazyCatalog, error) {
+	langs := make(map[string]struct{})
+
+	err := fs.WalkDir(fsys, dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if _, ok := CodecFor(d.Name()); d.IsDir() || !ok {
+			return nil
+		}
+		if lang := pathLanguage(path); lang != "" {
+			langs[lang] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []language.Tag
+	for _, lang := range slices.Sorted(maps.Keys(langs)) {
+		tags = append(tags, language.MustParse(lang))
+	}
+	return NewLazyCatalog(LocaleFS(fsys, dir), tags...), nil
+}
+
+func (c *LazyCatalog) Printer(tag language.Tag, key string) (*message.Printer, bool) {
+	p, ok := c.catalogPrinter(tag, key)
+	return p.Printer, ok
+}
+
+func (c *LazyCatalog) catalogPrinter(tag language.Tag, key string) (catalogPrinter, bool) {
+	locale, ok := c.match(tag)
+	if !ok {
+		return catalogPrinter{}, false
+	}
+
+	p, err := c.provider(locale, false)
+	if err != nil {
+		return catalogPrinter{}, false
+	}
+	return p.catalogPrinter(tag, key)
+}
+
+// Keys returns the keys of the catalog of the language closest to tag,
+// loading it if needed.
+func (c *LazyCatalog) Keys(tag language.Tag) iter.Seq[string] {
+	found := make(map[string]struct{})
+	if locale, ok := c.match(tag); ok {
+		if p, err := c.provider(locale, false); err == nil {
+			addKeys(found, p, tag)
+		}
+	}
+	return maps.Keys(found)
+}
+
+// Languages returns the languages of c.
+func (c *LazyCatalog) Languages() []language.Tag {
+	return slices.Clone(c.tags)
+}
+
+// Loaded returns the languages whose catalogs are loaded.
+func (c *LazyCatalog) Loaded() []language.Tag {
+	var loaded []language.Tag
+	for _, tag := range c.tags {
+		if c.locales[tag].provider.Load() != nil {
+			loaded = append(loaded, tag)
+		}
+	}
+	return loaded
+}
+
+// Load loads the catalog of the language closest to tag, unless it is
+// already loaded, and returns the error of the loader. Unlike uses, it
+// retries a failed load right away.
+func (c *LazyCatalog) Load(tag language.Tag) error {
+	locale, ok := c.match(tag)
+	if !ok {
+		return fmt.Errorf("no catalog for %s", tag)
+	}
+
+	_, err := c.provider(locale, true)
+	return err
+}
+
+// Err returns the errors of the languages whose last load failed, or nil.
+func (c *LazyCatalog) Err() error {
+	var errs []error
+	for _, tag := range c.tags {
+		errs = append(errs, c.locales[tag].error())
+	}
+	return errors.Join(errs...)
+}
+
+// SetRetryDelay sets how long after a failed load uses of the language are
+// served without its catalog before one loads it again, a second by
+// default. The delay doubles with every further failure, up to five minutes
+// or d if longer.
+func (c *LazyCatalog) SetRetryDelay(d time.Duration) {
+	c.retryDelay.Store(int64(d))
+}
+
+// Preload loads the catalogs of tags concurrently, e.g. the languages most
+// requests use, so the first requests do not wait for them.
+func (c *LazyCatalog) Preload(tags ...language.Tag) error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(tags))
+	)
+	for i, tag := range tags {
+		wg.Go(func() {
+			errs[i] = c.Load(tag)
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// match returns the language of c closest to tag.
+func (c *LazyCatalog) match(tag language.Tag) (language.Tag, bool) {
+	if len(c.tags) == 0 {
+		return language.Und, false
+	}
+
+	_, i, confidence := c.matcher.Match(tag)
+	if confidence == language.No {
+		return language.Und, false
+	}
+	return c.tags[i], true
+}
+
+// provider returns the provider of locale, a language of c, loading its
+// catalog if needed. Unless force is set, a failed load is retried only
+// after its delay.
+func (c *LazyCatalog) provider(locale language.Tag, force bool) (*catalogProvider, error) {
+	return c.locales[locale].get(func() (*catalogProvider, error) {
+		return c.loadLocale(locale)
+	}, c.backoff, force)
+}
+
+// backoff returns the delay before retrying a load that failed failures
+// times in a row.
+func (c *LazyCatalog) backoff(failures int) time.Duration {
+	d := time.Duration(c.retryDelay.Load())
+	limit := max(d, maxRetryDelay)
+	for i := 1; i < failures && d < limit; i++ {
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+func (c *LazyCatalog) loadLocale(tag language.Tag) (*catalogProvider, error) {
+	cat, err := c.load(tag)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", tag, err)
+	}
+	if cat == nil {
+		cat = catalog.NewBuilder()
+	}
+	return &catalogProvider{cat: cat}, nil
+}
+
+// lazyLocale is the catalog of one language of a LazyCatalog.
+type lazyLocale struct {
+	provider atomic.Pointer[catalogProvider]
+
+	mu       sync.Mutex
+	call     *localeCall // load in progress
+	err      error       // of the last load, if it failed
+	failures int         // failed loads in a row
+	retry    time.Time   // before which uses do not retry a failed load
+}
+
+// localeCall is a load waited for by every concurrent first use.
+type localeCall struct {
+	done     chan struct{}
+	provider *catalogProvider
+	err      error
+}
+
+// get returns the loaded provider, loading it with load if needed. Unless
+// force is set, the error of a failed load is returned until the delay
+// returned by backoff elapsed.
+func (l *lazyLocale) get(load func() (*catalogProvider, error), backoff func(failures int) time.Duration, force bool) (*catalogProvider, error) {
+	if p := l.provider.Load(); p != nil {
+		return p, nil
+	}
+
+	l.mu.Lock()
+	if p := l.provider.Load(); p != nil {
+		l.mu.Unlock()
+		return p, nil
+	}
+	if call := l.call; call != nil {
+		l.mu.Unlock()
+		<-call.done
+		return call.provider, call.err
+	}
+	if err := l.err; err != nil && !force && time.Now().Before(l.retry) {
+		l.mu.Unlock()
+		return nil, err
+	}
+
+	call := &localeCall{done: make(chan struct{})}
+	l.call = call
+	l.mu.Unlock()
+
+	call.provider, call.err = load()
+
+	l.mu.Lock()
+	if call.err == nil {
+		l.provider.Store(call.provider)
+		l.err, l.failures = nil, 0
+	} else {
+		l.err = call.err
+		l.failures++
+		l.retry = time.Now().Add(backoff(l.failures))
+	}
+	l.call = nil
+	l.mu.Unlock()
+	close(call.done)
+
+	return call.provider, call.err
+}
+
+// error returns the error of the last load, if it failed.
+func (l *lazyLocale) error() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
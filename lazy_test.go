@@ -0,0 +1,148 @@
+package i18n
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestLazyCatalogFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/de.yaml":          {Data: []byte("Save: Speichern\n")},
+		"locales/it/messages.toml": {Data: []byte(`Save = "Salva"`)},
+		"locales/fr.yaml":          {Data: []byte("Save: [")},
+		"locales/README.md":        {Data: []byte("not a translation file")},
+	}
+
+	c, err := NewLazyCatalogFS(fsys, "locales")
+	require.NoError(t, err)
+	assert.Equal(t, []language.Tag{language.German, language.French, language.Italian}, c.Languages())
+	assert.Empty(t, c.Loaded())
+
+	tr := NewTranslator(c)
+	assert.Equal(t, "Speichern", tr.T(language.MustParse("de-AT"), "Save"))
+	assert.Equal(t, []language.Tag{language.German}, c.Loaded())
+
+	t.Run("Only the files of the language are read", func(t *testing.T) {
+		require.NoError(t, c.Preload(language.Italian))
+		assert.Equal(t, "Salva", tr.T(language.Italian, "Save"))
+		assert.Equal(t, []language.Tag{language.German, language.Italian}, c.Loaded())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		assert.ErrorContains(t, c.Load(language.French), "load fr")
+		assert.Equal(t, "Save", tr.T(language.French, "Save"))
+		assert.ErrorContains(t, c.Err(), "load fr")
+		assert.EqualError(t, c.Preload(language.Japanese, language.German), "no catalog for ja")
+
+		_, err := NewLazyCatalogFS(fsys, "missing")
+		assert.Error(t, err)
+	})
+}
+
+func TestLazyCatalog(t *testing.T) {
+	t.Run("Concurrent first uses load once", func(t *testing.T) {
+		var (
+			loads   atomic.Int32
+			release = make(chan struct{})
+		)
+		c := NewLazyCatalog(func(tag language.Tag) (catalog.Catalog, error) {
+			loads.Add(1)
+			<-release
+			return newTestCatalog(t, tag, "Save", "Speichern"), nil
+		}, language.German)
+
+		var wg sync.WaitGroup
+		results := make([]string, 20)
+		for i := range results {
+			wg.Go(func() {
+				if p, ok := c.Printer(language.German, "Save"); ok {
+					results[i] = p.Sprintf("Save")
+				}
+			})
+		}
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), loads.Load())
+		for _, r := range results {
+			assert.Equal(t, "Speichern", r)
+		}
+	})
+
+	t.Run("Failed loads are retried after a delay", func(t *testing.T) {
+		var loads atomic.Int32
+		c := NewLazyCatalog(func(tag language.Tag) (catalog.Catalog, error) {
+			if loads.Add(1) <= 2 {
+				return nil, errors.New("unavailable")
+			}
+			return newTestCatalog(t, tag, "Save", "Speichern"), nil
+		}, language.German)
+
+		_, ok := c.Printer(language.German, "Save")
+		assert.False(t, ok)
+		assert.EqualError(t, c.Err(), "load de: unavailable")
+
+		_, ok = c.Printer(language.German, "Save")
+		assert.False(t, ok)
+		assert.Empty(t, slices.Collect(c.Keys(language.German)))
+		assert.Equal(t, int32(1), loads.Load())
+
+		assert.EqualError(t, c.Load(language.German), "load de: unavailable")
+		assert.Equal(t, int32(2), loads.Load())
+		assert.Empty(t, c.Loaded())
+
+		c.locales[language.German].retry = time.Time{}
+		_, ok = c.Printer(language.German, "Save")
+		assert.True(t, ok)
+		assert.Equal(t, int32(3), loads.Load())
+		assert.NoError(t, c.Err())
+
+		require.NoError(t, c.Load(language.German))
+		assert.Equal(t, int32(3), loads.Load())
+	})
+
+	t.Run("Without a retry delay every use retries", func(t *testing.T) {
+		var loads atomic.Int32
+		c := NewLazyCatalog(func(language.Tag) (catalog.Catalog, error) {
+			loads.Add(1)
+			return nil, errors.New("unavailable")
+		}, language.German)
+		c.SetRetryDelay(0)
+
+		for range 3 {
+			_, ok := c.Printer(language.German, "Save")
+			assert.False(t, ok)
+		}
+		assert.Equal(t, int32(3), loads.Load())
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		c := NewLazyCatalog(nil)
+		assert.Equal(t, time.Second, c.backoff(1))
+		assert.Equal(t, 4*time.Second, c.backoff(3))
+		assert.Equal(t, 5*time.Minute, c.backoff(20))
+
+		c.SetRetryDelay(time.Hour)
+		assert.Equal(t, time.Hour, c.backoff(3))
+	})
+
+	t.Run("Empty catalogs", func(t *testing.T) {
+		c := NewLazyCatalog(func(language.Tag) (catalog.Catalog, error) { return nil, nil }, language.German)
+		_, ok := c.Printer(language.German, "Save")
+		assert.False(t, ok)
+		assert.Equal(t, []language.Tag{language.German}, c.Loaded())
+
+		_, ok = NewLazyCatalog(nil).Printer(language.German, "Save")
+		assert.False(t, ok)
+	})
+}
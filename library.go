@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// RegisterLibrary adds the translations of a library, such as a module of
+// shared UI components, read from the translation files in dir of fsys like
+// LoadTranslations. It is meant to be called from an init function of the
+// library with its embedded files:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	func init() {
+//		if err := i18n.RegisterLibrary("github.com/acme/ui", locales, "locales"); err != nil {
+//			panic(err)
+//		}
+//	}
+//
+// Library translations are used only for messages the application does not
+// translate itself, see Translator.SetLibrary. Registering a name again
+// replaces its translations.
+func RegisterLibrary(name string, fsys fs.FS, dir string) error {
+	b := newKeyedCatalog()
+	if err := loadTranslations(fsys, dir, b.Set); err != nil {
+		return fmt.Errorf("library %s: %w", name, err)
+	}
+	std.SetLibrary(name, NewCatalogProvider(b))
+	return nil
+}
+
+// library is a provider of translations registered by a library.
+type library struct {
+	name     string
+	provider CatalogProvider
+}
+
+// SetLibrary sets the translations of the library name. They are consulted,
+// in registration order, after the provider of tr and the active catalog,
+// so applications can override any library message. A nil provider removes
+// the library.
+func (tr *Translator) SetLibrary(name string, provider CatalogProvider) {
+	tr.librariesMu.Lock()
+	defer tr.librariesMu.Unlock()
+
+	var list []library
+	if p := tr.libraries.Load(); p != nil {
+		list = slices.Clone(*p)
+	}
+
+	i := slices.IndexFunc(list, func(l library) bool { return l.name == name })
+	switch {
+	case provider == nil && i >= 0:
+		list = slices.Delete(list, i, i+1)
+	case provider == nil:
+	case i >= 0:
+		list[i].provider = provider
+	default:
+		list = append(list, library{name: name, provider: provider})
+	}
+	tr.libraries.Store(&list)
+}
+
+// libraryPrinter returns the printer of the first library that has key in
+// tag, unless the application translates it, see appLookup.
+func (tr *Translator) libraryPrinter(tag language.Tag, key string) (catalogPrinter, bool) {
+	list := tr.libraries.Load()
+	if list == nil || len(*list) == 0 || key == "" {
+		return catalogPrinter{}, false
+	}
+	if _, ok := appLookup(tag, key); ok {
+		return catalogPrinter{}, false
+	}
+
+	for _, l := range *list {
+		if printer, ok := providerPrinter(l.provider, tag, key); ok {
+			return printer, true
+		}
+	}
+	return catalogPrinter{}, false
+}
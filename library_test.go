@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestSetLibrary(t *testing.T) {
+	tag := language.MustParse("lb")
+	require.NoError(t, Set(tag, "test.library.app", "App"))
+
+	tr := NewTranslator(nil)
+	ui := newTestCatalog(t, tag, "test.library.save", "UI Späicheren", "test.library.app", "UI App", "Hello %s", "UI Moien %s")
+	forms := newTestCatalog(t, tag, "test.library.save", "Forms Späicheren", "test.library.forms", "Forms")
+
+	tr.SetLibrary("ui", NewCatalogProvider(ui))
+	tr.SetLibrary("forms", NewCatalogProvider(forms))
+
+	t.Run("Library translations", func(t *testing.T) {
+		assert.Equal(t, "UI Späicheren", tr.T(tag, Key("test.library.save", "Save")))
+		assert.Equal(t, "Forms", tr.T(tag, Key("test.library.forms", "Forms")))
+		assert.Equal(t, "UI Moien Bob", tr.T(tag, "Hello %s", "Bob"))
+	})
+
+	t.Run("Application catalogs take precedence", func(t *testing.T) {
+		assert.Equal(t, "App", tr.T(tag, Key("test.library.app", "App")))
+
+		tr.SetProvider(NewCatalogProvider(newTestCatalog(t, tag, "test.library.save", "Provider")))
+		t.Cleanup(func() { tr.SetProvider(nil) })
+		assert.Equal(t, "Provider", tr.T(tag, Key("test.library.save", "Save")))
+	})
+
+	t.Run("Application catalogs take precedence in the matched language", func(t *testing.T) {
+		t.Cleanup(func() {
+			SetLanguages()
+			printers.Delete(tag)
+		})
+		SetLanguages(tag)
+
+		regional := language.MustParse("lb-LU")
+		assert.Equal(t, "App", tr.T(regional, Key("test.library.app", "App")))
+		assert.Equal(t, "App", tr.T(language.Japanese, Key("test.library.app", "App")))
+
+		tr.SetLibrary("ja", NewCatalogProvider(newTestCatalog(t, language.Japanese, "test.library.app", "Library ja")))
+		t.Cleanup(func() { tr.SetLibrary("ja", nil) })
+		assert.Equal(t, "App", tr.T(language.Japanese, Key("test.library.app", "App")))
+
+		SetPrinterCatalog(tag, newTestCatalog(t, tag, "test.library.save", "App Späicheren"))
+		assert.Equal(t, "App Späicheren", tr.T(regional, Key("test.library.save", "Save")))
+		msg, ok := tr.Lookup(regional, "test.library.save")
+		assert.True(t, ok)
+		assert.Equal(t, "App Späicheren", msg)
+	})
+
+	t.Run("Source language is printed as written", func(t *testing.T) {
+		assert.Equal(t, "Save", tr.T(SourceLanguage(), Key("test.library.save", "Save")))
+		assert.Equal(t, "Save", tr.T(language.German, Key("test.library.save", "Save")))
+	})
+
+	t.Run("Replacing keeps the order and removing drops the library", func(t *testing.T) {
+		tr.SetLibrary("ui", NewCatalogProvider(newTestCatalog(t, tag, "test.library.save", "UI v2")))
+		assert.Equal(t, "UI v2", tr.T(tag, Key("test.library.save", "Save")))
+
+		tr.SetLibrary("ui", nil)
+		assert.Equal(t, "Forms Späicheren", tr.T(tag, Key("test.library.save", "Save")))
+
+		tr.SetLibrary("unknown", nil)
+		tr.SetLibrary("forms", nil)
+		assert.Equal(t, "Save", tr.T(tag, Key("test.library.save", "Save")))
+	})
+}
+
+func TestRegisterLibrary(t *testing.T) {
+	t.Cleanup(func() { std.SetLibrary("example.com/ui", nil) })
+
+	fsys := fstest.MapFS{
+		"locales/lb.yaml": {Data: []byte("test.register.cancel: Ofbriechen\n")},
+	}
+	require.NoError(t, RegisterLibrary("example.com/ui", fsys, "locales"))
+	assert.Equal(t, "Ofbriechen", T(language.MustParse("lb"), Key("test.register.cancel", "Cancel")))
+
+	err := RegisterLibrary("example.com/broken", fstest.MapFS{"locales/lb.yaml": {Data: []byte("a: [")}}, "locales")
+	assert.ErrorContains(t, err, "library example.com/broken")
+}
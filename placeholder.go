@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/message"
+)
+
+var rePlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Placeholders returns the unique named placeholders of s (e.g. "name" for
+// "Hello {name}") in order of first appearance.
+func Placeholders(s string) []string {
+	var names []string
+	for _, m := range rePlaceholder.FindAllStringSubmatch(s, -1) {
+		if !slices.Contains(names, m[1]) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// ValidatePlaceholders reports an error when translation does not use exactly
+// the same set of named placeholders as source.
+func ValidatePlaceholders(source, translation string) error {
+	want := Placeholders(source)
+	got := Placeholders(translation)
+
+	var missing, unknown []string
+	for _, name := range want {
+		if !slices.Contains(got, name) {
+			missing = append(missing, "{"+name+"}")
+		}
+	}
+	for _, name := range got {
+		if !slices.Contains(want, name) {
+			unknown = append(unknown, "{"+name+"}")
+		}
+	}
+
+	switch {
+	case len(missing) > 0 && len(unknown) > 0:
+		return fmt.Errorf("missing placeholders %s, unknown placeholders %s", strings.Join(missing, ", "), strings.Join(unknown, ", "))
+	case len(missing) > 0:
+		return fmt.Errorf("missing placeholders %s", strings.Join(missing, ", "))
+	case len(unknown) > 0:
+		return fmt.Errorf("unknown placeholders %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// namedArgs reports whether a holds named arguments, either as a single map or
+// as alternating name/value pairs, and returns them as a map.
+func namedArgs(a []any) (map[string]any, bool) {
+	if len(a) == 1 {
+		switch m := a[0].(type) {
+		case map[string]any:
+			return m, true
+		case map[string]string:
+			named := make(map[string]any, len(m))
+			for k, v := range m {
+				named[k] = v
+			}
+			return named, true
+		}
+	}
+
+	if len(a) == 0 || len(a)%2 != 0 {
+		return nil, false
+	}
+
+	named := make(map[string]any, len(a)/2)
+	for i := 0; i < len(a); i += 2 {
+		name, ok := a[i].(string)
+		if !ok {
+			return nil, false
+		}
+		named[name] = a[i+1]
+	}
+	return named, true
+}
+
+// expandPlaceholders replaces every {name} in s with the value formatted by
+// p. Placeholders without a value are left untouched.
+func expandPlaceholders(s string, named map[string]any, p *message.Printer) string {
+	return rePlaceholder.ReplaceAllStringFunc(s, func(m string) string {
+		if v, ok := named[m[1:len(m)-1]]; ok {
+			return p.Sprint(v)
+		}
+		return m
+	})
+}
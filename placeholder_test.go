@@ -0,0 +1,141 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"no placeholders", "Hello world", nil},
+		{"printf verbs", "Hello %s, you have %d messages", nil},
+		{"single placeholder", "Hello {name}", []string{"name"}},
+		{"order of appearance", "{count} messages for {name}", []string{"count", "name"}},
+		{"duplicates", "{name} and {name}", []string{"name"}},
+		{"invalid names", "{1st} { name } {} {first_name2}", []string{"first_name2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Placeholders(tt.input))
+		})
+	}
+}
+
+func TestValidatePlaceholders(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		translation string
+		err         string
+	}{
+		{"same set", "Hello {name}, you have {count} messages", "Sie haben {count} Nachrichten, {name}", ""},
+		{"no placeholders", "Hello", "Hallo", ""},
+		{"missing", "Hello {name}", "Hallo", "missing placeholders {name}"},
+		{"unknown", "Hello", "Hallo {name}", "unknown placeholders {name}"},
+		{"renamed", "Hello {name}", "Hallo {nom}", "missing placeholders {name}, unknown placeholders {nom}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePlaceholders(tt.source, tt.translation)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNamedArgs(t *testing.T) {
+	t.Run("Pairs", func(t *testing.T) {
+		named, ok := namedArgs([]any{"name", "Bob", "count", 3})
+		require.True(t, ok)
+		assert.Equal(t, map[string]any{"name": "Bob", "count": 3}, named)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		named, ok := namedArgs([]any{map[string]any{"name": "Bob"}})
+		require.True(t, ok)
+		assert.Equal(t, map[string]any{"name": "Bob"}, named)
+	})
+
+	t.Run("String map", func(t *testing.T) {
+		named, ok := namedArgs([]any{map[string]string{"name": "Bob"}})
+		require.True(t, ok)
+		assert.Equal(t, map[string]any{"name": "Bob"}, named)
+	})
+
+	t.Run("Not named", func(t *testing.T) {
+		for _, a := range [][]any{nil, {"Bob"}, {1, "Bob"}, {"name", "Bob", "count"}} {
+			_, ok := namedArgs(a)
+			assert.False(t, ok, "%v", a)
+		}
+	})
+}
+
+func TestTNamedPlaceholders(t *testing.T) {
+	t.Run("Pairs", func(t *testing.T) {
+		result := T(language.English, "Hello {name}, you have {count} messages", "name", "Alice", "count", 1200)
+		assert.Equal(t, "Hello Alice, you have 1,200 messages", result)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		result := T(language.German, "{count} Nachrichten", map[string]any{"count": 1200})
+		assert.Equal(t, "1.200 Nachrichten", result)
+	})
+
+	t.Run("Missing value is kept", func(t *testing.T) {
+		result := T(language.English, "Hello {name} {unknown}", "name", "Alice")
+		assert.Equal(t, "Hello Alice {unknown}", result)
+	})
+
+	t.Run("Translated order", func(t *testing.T) {
+		tag := language.Swedish
+		cat := catalog.NewBuilder()
+		require.NoError(t, cat.SetString(tag, "{name} has {count} new messages", "{count} new messages for {name}"))
+		SetPrinter(tag, message.NewPrinter(tag, message.Catalog(cat)))
+
+		result := T(tag, "{name} has {count} new messages", "name", "Alice", "count", 2)
+		assert.Equal(t, "2 new messages for Alice", result)
+	})
+
+	t.Run("Literal percent signs", func(t *testing.T) {
+		assert.Equal(t, "100% Bob", T(language.Dutch, "100% {name}", "name", "Bob"))
+
+		tag := language.MustParse("gsw")
+		require.NoError(t, Set(tag, "test.placeholder.discount", "50% Rabatt für {name}"))
+		assert.Equal(t, "50% Rabatt für Bob", T(tag, Key("test.placeholder.discount", "50% off for {name}"), "name", "Bob"))
+		assert.Equal(t, "50% off for Bob", T(language.French, Key("test.placeholder.discount", "50% off for {name}"), "name", "Bob"))
+
+		tr := NewTranslator(NewCatalogProvider(newTestCatalog(t, tag, "{count}% done", "{count}% fertig")))
+		assert.Equal(t, "12% fertig", tr.T(tag, "{count}% done", "count", 12))
+
+		tag = language.MustParse("rm")
+		SetPrinterCatalog(tag, newTestCatalog(t, tag, "{count}% done", "{count}% fatg"))
+		t.Cleanup(func() { printers.Delete(tag) })
+		assert.Equal(t, "12% fatg", T(tag, "{count}% done", "count", 12))
+	})
+
+	t.Run("Template pairs and map", func(t *testing.T) {
+		trans := FuncMap["T"].(func(any, message.Reference, ...any) string)
+
+		assert.Equal(t, "Hi Bob", trans("en", "Hi {name}", "name", "Bob"))
+		assert.Equal(t, "Hi Bob", trans("en", "Hi {name}", map[string]string{"name": "Bob"}))
+	})
+
+	t.Run("Thtml escapes named values", func(t *testing.T) {
+		assert.Equal(t, "<b>&lt;Bob&gt;</b>", string(Thtml(language.English, "<b>{name}</b>", "name", "<Bob>")))
+		assert.Equal(t, "<b>&lt;Bob&gt;</b>", string(Thtml(language.English, "<b>{name}</b>", map[string]any{"name": "<Bob>"})))
+	})
+}
@@ -0,0 +1,168 @@
+package i18n
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// DefaultPrinterCacheSize is the number of printers kept for distinct tags
+// while no supported languages are set.
+const DefaultPrinterCacheSize = 64
+
+var (
+	supported atomic.Pointer[languageSet]
+	unmatched = newPrinterCache(DefaultPrinterCacheSize)
+)
+
+// catalogPrinter is a printer together with the catalog and language of its
+// messages, so that messages with named placeholders can be read from the
+// catalog unformatted. The catalog of printers created elsewhere, such as
+// the ones registered with SetPrinter, is unknown and nil.
+type catalogPrinter struct {
+	*message.Printer
+	cat catalog.Catalog
+	tag language.Tag
+}
+
+// newPrinter returns the printer of tag formatting the messages of cat.
+func newPrinter(tag language.Tag, cat catalog.Catalog) catalogPrinter {
+	return catalogPrinter{Printer: message.NewPrinter(tag, message.Catalog(cat)), cat: cat, tag: tag}
+}
+
+// text returns the message of id as the printer would look it up, or else
+// of source, without formatting it, or source if there is none. Without a
+// catalog it returns key formatted without arguments.
+func (p catalogPrinter) text(key message.Reference, id, source string) string {
+	if p.cat == nil {
+		return p.Sprintf(key)
+	}
+
+	if text, ok := lookup(p.cat, p.tag, id); ok {
+		return text
+	}
+	if id != source {
+		if text, ok := lookup(p.cat, p.tag, source); ok {
+			return text
+		}
+	}
+	return source
+}
+
+// languageSet is the set of supported languages and their matcher.
+type languageSet struct {
+	tags    []language.Tag
+	matcher language.Matcher
+}
+
+// SetLanguages sets the supported languages, the first being the default.
+// Printer then maps every tag to the closest supported language, so no more
+// printers than languages are created however many distinct tags are seen.
+// Without languages every tag gets its own printer, see SetPrinterCacheSize.
+func SetLanguages(tags ...language.Tag) {
+	if len(tags) == 0 {
+		supported.Store(nil)
+		return
+	}
+
+	tags = slices.Clone(tags)
+	supported.Store(&languageSet{tags: tags, matcher: language.NewMatcher(tags)})
+}
+
+// Languages returns the supported languages set with SetLanguages or,
+// without them, the languages of the active catalog.
+func Languages() []language.Tag {
+	if set := supported.Load(); set != nil {
+		return slices.Clone(set.tags)
+	}
+	return message.DefaultCatalog.Languages()
+}
+
+// SetPrinterCacheSize bounds the printers created for distinct tags while no
+// supported languages are set; the least recently used are dropped. A size
+// of zero or less removes the bound. Printers registered with SetPrinter are
+// never dropped.
+func SetPrinterCacheSize(size int) {
+	unmatched.resize(size)
+}
+
+// match returns the supported language closest to tag, or the default one.
+func (s *languageSet) match(tag language.Tag) language.Tag {
+	_, i, _ := s.matcher.Match(tag)
+	return s.tags[i]
+}
+
+// resolve returns the language tag is printed in: the closest supported
+// one or, for tags of the source language, the source language.
+func (s *languageSet) resolve(tag language.Tag) language.Tag {
+	if matched := s.match(tag); !isSource(tag) || isSource(matched) {
+		return matched
+	}
+	return SourceLanguage()
+}
+
+// printerCache is a least recently used cache of printers by tag.
+type printerCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // of *printerEntry, most recently used first
+	items map[language.Tag]*list.Element
+}
+
+type printerEntry struct {
+	tag     language.Tag
+	printer catalogPrinter
+}
+
+func newPrinterCache(size int) *printerCache {
+	return &printerCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[language.Tag]*list.Element),
+	}
+}
+
+// get returns the printer of tag, creating it if needed.
+func (c *printerCache) get(tag language.Tag) catalogPrinter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[tag]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*printerEntry).printer
+	}
+
+	printer := newPrinter(tag, message.DefaultCatalog)
+	c.items[tag] = c.order.PushFront(&printerEntry{tag: tag, printer: printer})
+	c.evict()
+
+	return printer
+}
+
+func (c *printerCache) resize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.size = size
+	c.evict()
+}
+
+func (c *printerCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *printerCache) evict() {
+	for c.size > 0 && c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.items, el.Value.(*printerEntry).tag)
+	}
+}
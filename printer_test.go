@@ -0,0 +1,76 @@
+package i18n
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+func TestSetLanguages(t *testing.T) {
+	t.Cleanup(func() { SetLanguages() })
+
+	SetLanguages(language.English, language.German, language.MustParse("es-419"))
+	assert.Equal(t, []language.Tag{language.English, language.German, language.MustParse("es-419")}, Languages())
+
+	t.Run("Tags map to the closest supported language", func(t *testing.T) {
+		de := Printer(language.German)
+		assert.Same(t, de, Printer(language.MustParse("de-AT")))
+		assert.Same(t, de, Printer(language.MustParse("de-CH-u-co-phonebk")))
+		assert.Same(t, Printer(language.MustParse("es-419")), Printer(language.MustParse("es-AR")))
+	})
+
+	t.Run("Unsupported tags use the default language", func(t *testing.T) {
+		en := Printer(language.English)
+		for i := range 100 {
+			assert.Same(t, en, Printer(language.MustParse(fmt.Sprintf("x-a%d", i))))
+		}
+		assert.Same(t, en, Printer(language.Japanese))
+	})
+
+	t.Run("Registered printers take precedence", func(t *testing.T) {
+		custom := message.NewPrinter(language.MustParse("de-AT"))
+		SetPrinter(language.MustParse("de-AT"), custom)
+		t.Cleanup(func() { printers.Delete(language.MustParse("de-AT")) })
+
+		assert.Same(t, custom, Printer(language.MustParse("de-AT")))
+	})
+
+	SetLanguages()
+	assert.Equal(t, message.DefaultCatalog.Languages(), Languages())
+}
+
+func TestPrinterCache(t *testing.T) {
+	c := newPrinterCache(2)
+
+	en := c.get(language.English).Printer
+	de := c.get(language.German).Printer
+	assert.Same(t, en, c.get(language.English).Printer)
+
+	// German is the least recently used
+	c.get(language.French)
+	assert.Equal(t, 2, c.len())
+	assert.Same(t, en, c.get(language.English).Printer)
+	assert.NotSame(t, de, c.get(language.German).Printer)
+
+	c.resize(1)
+	assert.Equal(t, 1, c.len())
+
+	c.resize(0)
+	for i := range 10 {
+		c.get(language.MustParse(fmt.Sprintf("x-t%d", i)))
+	}
+	assert.Equal(t, 11, c.len())
+}
+
+func TestSetPrinterCacheSize(t *testing.T) {
+	t.Cleanup(func() { SetPrinterCacheSize(DefaultPrinterCacheSize) })
+
+	SetPrinterCacheSize(3)
+	for i := range 10 {
+		Printer(language.MustParse(fmt.Sprintf("x-c%d", i)))
+	}
+	assert.LessOrEqual(t, unmatched.len(), 3)
+}
@@ -0,0 +1,169 @@
+package i18n
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// CatalogProvider is a source of translations consulted per lookup.
+type CatalogProvider interface {
+	// Printer returns the printer formatting the messages of the provider in
+	// tag, if the provider has a message for key in tag.
+	Printer(tag language.Tag, key string) (*message.Printer, bool)
+}
+
+// KeyLister is implemented by providers and catalogs that can list their
+// message keys, see Keys.
+type KeyLister interface {
+	// Keys returns the keys that have a message in tag.
+	Keys(tag language.Tag) iter.Seq[string]
+}
+
+// Layered is a CatalogProvider consulting its providers in priority order,
+// e.g. tenant overrides, then the product catalog, then library defaults.
+type Layered []CatalogProvider
+
+func (l Layered) Printer(tag language.Tag, key string) (*message.Printer, bool) {
+	p, ok := l.catalogPrinter(tag, key)
+	return p.Printer, ok
+}
+
+func (l Layered) catalogPrinter(tag language.Tag, key string) (catalogPrinter, bool) {
+	for _, p := range l {
+		if p == nil {
+			continue
+		}
+		if printer, ok := providerPrinter(p, tag, key); ok {
+			return printer, true
+		}
+	}
+	return catalogPrinter{}, false
+}
+
+// Keys returns the keys of the providers implementing KeyLister.
+func (l Layered) Keys(tag language.Tag) iter.Seq[string] {
+	found := make(map[string]struct{})
+	for _, p := range l {
+		addKeys(found, p, tag)
+	}
+	return maps.Keys(found)
+}
+
+// addKeys adds the keys of v in tag to found, if v implements KeyLister.
+func addKeys(found map[string]struct{}, v any, tag language.Tag) {
+	if l, ok := v.(KeyLister); ok {
+		for key := range l.Keys(tag) {
+			found[key] = struct{}{}
+		}
+	}
+}
+
+// catalogPrinterProvider is implemented by the providers of this package,
+// which return their printers with the catalog they format.
+type catalogPrinterProvider interface {
+	catalogPrinter(tag language.Tag, key string) (catalogPrinter, bool)
+}
+
+// providerPrinter returns the printer of provider for key in tag, with its
+// catalog if provider knows it.
+func providerPrinter(provider CatalogProvider, tag language.Tag, key string) (catalogPrinter, bool) {
+	if p, ok := provider.(catalogPrinterProvider); ok {
+		return p.catalogPrinter(tag, key)
+	}
+	printer, ok := provider.Printer(tag, key)
+	return catalogPrinter{Printer: printer}, ok
+}
+
+// catalogProvider provides the messages of a catalog.Catalog.
+type catalogProvider struct {
+	cat      catalog.Catalog
+	printers sync.Map // of catalogPrinter by catalog language
+}
+
+// NewCatalogProvider returns a CatalogProvider of the messages in cat. Tags
+// are matched to the languages of cat, so at most one printer per language
+// is created. The provider lists its keys if cat implements KeyLister.
+func NewCatalogProvider(cat catalog.Catalog) CatalogProvider {
+	return &catalogProvider{cat: cat}
+}
+
+func (c *catalogProvider) Printer(tag language.Tag, key string) (*message.Printer, bool) {
+	p, ok := c.catalogPrinter(tag, key)
+	return p.Printer, ok
+}
+
+func (c *catalogProvider) catalogPrinter(tag language.Tag, key string) (catalogPrinter, bool) {
+	tag, ok := c.match(tag)
+	if !ok {
+		return catalogPrinter{}, false
+	}
+
+	if _, ok := lookup(c.cat, tag, key); !ok {
+		return catalogPrinter{}, false
+	}
+	return c.printer(tag), true
+}
+
+// Keys returns the keys of the catalog in the language closest to tag, if
+// the catalog implements KeyLister.
+func (c *catalogProvider) Keys(tag language.Tag) iter.Seq[string] {
+	found := make(map[string]struct{})
+	if tag, ok := c.match(tag); ok {
+		addKeys(found, c.cat, tag)
+	}
+	return maps.Keys(found)
+}
+
+// printer returns the printer of tag, a language of the catalog.
+func (c *catalogProvider) printer(tag language.Tag) catalogPrinter {
+	if v, ok := c.printers.Load(tag); ok {
+		return v.(catalogPrinter)
+	}
+	v, _ := c.printers.LoadOrStore(tag, newPrinter(tag, c.cat))
+	return v.(catalogPrinter)
+}
+
+// match returns the language of the catalog closest to tag.
+func (c *catalogProvider) match(tag language.Tag) (language.Tag, bool) {
+	langs := c.cat.Languages()
+	if len(langs) == 0 {
+		return language.Und, false
+	}
+
+	_, i, confidence := c.cat.Matcher().Match(tag)
+	if confidence == language.No || i >= len(langs) {
+		return language.Und, false
+	}
+	return langs[i], true
+}
+
+// lookup returns the message of key in tag as stored in cat, without
+// formatting its arguments.
+func lookup(cat catalog.Catalog, tag language.Tag, key string) (string, bool) {
+	var r textRenderer
+	err := cat.Context(tag, &r).Execute(key)
+	if errors.Is(err, catalog.ErrNotFound) {
+		return "", false
+	}
+	return r.String(), true
+}
+
+// textRenderer collects the text a catalog message renders.
+type textRenderer struct {
+	strings.Builder
+}
+
+func (r *textRenderer) Render(s string) {
+	r.WriteString(s)
+}
+
+func (r *textRenderer) Arg(int) any {
+	return nil
+}
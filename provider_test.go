@@ -0,0 +1,72 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+func newTestCatalog(t *testing.T, tag language.Tag, kv ...string) catalog.Catalog {
+	t.Helper()
+
+	cat := catalog.NewBuilder()
+	for i := 0; i+1 < len(kv); i += 2 {
+		require.NoError(t, cat.SetString(tag, kv[i], kv[i+1]))
+	}
+	return cat
+}
+
+func TestCatalogProvider(t *testing.T) {
+	p := NewCatalogProvider(newTestCatalog(t, language.German, "Hello", "Hallo"))
+
+	t.Run("Known key", func(t *testing.T) {
+		printer, ok := p.Printer(language.German, "Hello")
+		require.True(t, ok)
+		assert.Equal(t, "Hallo", printer.Sprintf("Hello"))
+	})
+
+	t.Run("Regional tags share the printer", func(t *testing.T) {
+		de, _ := p.Printer(language.German, "Hello")
+		at, ok := p.Printer(language.MustParse("de-AT"), "Hello")
+		require.True(t, ok)
+		assert.Same(t, de, at)
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		_, ok := p.Printer(language.German, "Goodbye")
+		assert.False(t, ok)
+	})
+
+	t.Run("Unsupported language", func(t *testing.T) {
+		_, ok := p.Printer(language.Japanese, "Hello")
+		assert.False(t, ok)
+	})
+
+	t.Run("Empty catalog", func(t *testing.T) {
+		_, ok := NewCatalogProvider(catalog.NewBuilder()).Printer(language.German, "Hello")
+		assert.False(t, ok)
+	})
+}
+
+func TestLayered(t *testing.T) {
+	tenant := NewCatalogProvider(newTestCatalog(t, language.German, "Pay now", "Jetzt bezahlen, Acme"))
+	product := NewCatalogProvider(newTestCatalog(t, language.German, "Pay now", "Jetzt bezahlen", "Cancel", "Abbrechen"))
+	l := Layered{tenant, nil, product}
+
+	printer, ok := l.Printer(language.German, "Pay now")
+	require.True(t, ok)
+	assert.Equal(t, "Jetzt bezahlen, Acme", printer.Sprintf("Pay now"))
+
+	printer, ok = l.Printer(language.German, "Cancel")
+	require.True(t, ok)
+	assert.Equal(t, "Abbrechen", printer.Sprintf("Cancel"))
+
+	_, ok = l.Printer(language.German, "Back")
+	assert.False(t, ok)
+
+	_, ok = Layered{}.Printer(language.German, "Cancel")
+	assert.False(t, ok)
+}
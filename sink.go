@@ -0,0 +1,62 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// Sink receives the files an Extractor writes: the messages JSON and the
+// synthetic Go file.
+type Sink interface {
+	WriteFile(name string, data []byte) error
+}
+
+// ReadSink is a Sink that can read back the files it holds. The Extractor
+// skips writing files whose content is unchanged.
+type ReadSink interface {
+	Sink
+	ReadFile(name string) ([]byte, error)
+}
+
+// DirSink writes files to the OS filesystem. Relative names are resolved
+// against the working directory. It is the default Sink.
+type DirSink struct{}
+
+func (DirSink) WriteFile(name string, data []byte) error {
+	return os.WriteFile(name, data, 0644)
+}
+
+func (DirSink) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// MemorySink keeps written files in memory, e.g. for tests or tooling that
+// post-processes the output.
+type MemorySink struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (s *MemorySink) WriteFile(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.files == nil {
+		s.files = make(map[string][]byte)
+	}
+	s.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+// ReadFile returns the content last written to name.
+func (s *MemorySink) ReadFile(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
@@ -0,0 +1,38 @@
+package i18n
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.json")
+
+	require.NoError(t, DirSink{}.WriteFile(path, []byte("[]")))
+
+	data, err := DirSink{}.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+
+	_, err = DirSink{}.ReadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestMemorySink(t *testing.T) {
+	var sink MemorySink
+
+	_, err := sink.ReadFile("messages.json")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	data := []byte("[]")
+	require.NoError(t, sink.WriteFile("messages.json", data))
+	data[0] = '{'
+
+	got, err := sink.ReadFile("messages.json")
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(got))
+}
@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"context"
+	"html/template"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant identifier, which
+// selects the overrides set with SetTenantCatalog.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant identifier of ctx set by WithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// SetTenantCatalog sets the overrides of tenant used by TContext,
+// ThtmlContext and NewFuncMapContext. A nil provider removes them.
+func SetTenantCatalog(tenant string, provider CatalogProvider) {
+	std.SetTenantCatalog(tenant, provider)
+}
+
+// TContext is like T, preferring the overrides of the tenant of ctx.
+func TContext(ctx context.Context, tag language.Tag, key message.Reference, a ...any) string {
+	return std.TContext(ctx, tag, key, a...)
+}
+
+// ThtmlContext is like Thtml, preferring the overrides of the tenant of ctx.
+func ThtmlContext(ctx context.Context, tag language.Tag, key message.Reference, a ...any) template.HTML {
+	return std.ThtmlContext(ctx, tag, key, a...)
+}
+
+// NewFuncMapContext is like NewFuncMap, preferring the overrides of the
+// tenant of ctx. It is meant for a per-request clone of a template.
+func NewFuncMapContext(ctx context.Context, funcs ...Func) template.FuncMap {
+	return std.FuncMapContext(ctx, funcs...)
+}
+
+// SetTenantCatalog sets the overrides of tenant, consulted before the
+// provider of tr. Every tenant should have its own provider, so printers
+// of one tenant are never used for another. A nil provider removes them.
+func (tr *Translator) SetTenantCatalog(tenant string, provider CatalogProvider) {
+	if provider == nil {
+		tr.tenants.Delete(tenant)
+		return
+	}
+	tr.tenants.Store(tenant, provider)
+}
+
+// SetTenantFunc sets how the tenant identifier is resolved from a context,
+// TenantFromContext by default.
+func (tr *Translator) SetTenantFunc(fn func(context.Context) (string, bool)) {
+	if fn == nil {
+		tr.tenantFn.Store(nil)
+		return
+	}
+	tr.tenantFn.Store(&fn)
+}
+
+// TContext is like T, preferring the overrides of the tenant of ctx.
+func (tr *Translator) TContext(ctx context.Context, tag language.Tag, key message.Reference, a ...any) string {
+	return tr.view(ctx).t(tag, key, a...)
+}
+
+// ThtmlContext is like Thtml, preferring the overrides of the tenant of ctx.
+func (tr *Translator) ThtmlContext(ctx context.Context, tag language.Tag, key message.Reference, a ...any) template.HTML {
+	return tr.view(ctx).thtml(tag, key, a...)
+}
+
+// FuncMapContext is like FuncMap, preferring the overrides of the tenant of
+// ctx.
+func (tr *Translator) FuncMapContext(ctx context.Context, funcs ...Func) template.FuncMap {
+	return tr.view(ctx).funcMap(funcs)
+}
+
+func (tr *Translator) view(ctx context.Context) view {
+	fn := TenantFromContext
+	if p := tr.tenantFn.Load(); p != nil {
+		fn = *p
+	}
+
+	tenant, ok := fn(ctx)
+	if !ok {
+		return view{tr: tr}
+	}
+	return view{tr: tr, tenant: tenant}
+}
@@ -0,0 +1,101 @@
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestTenantFromContext(t *testing.T) {
+	_, ok := TenantFromContext(context.Background())
+	assert.False(t, ok)
+
+	_, ok = TenantFromContext(WithTenant(context.Background(), ""))
+	assert.False(t, ok)
+
+	tenant, ok := TenantFromContext(WithTenant(context.Background(), "acme"))
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+}
+
+func TestTranslatorTenants(t *testing.T) {
+	tag := language.MustParse("x-tn")
+	tr := NewTranslator(NewCatalogProvider(newTestCatalog(t, tag, "Project", "Projekt", "New %s", "Neu: %s")))
+	tr.SetTenantCatalog("acme", NewCatalogProvider(newTestCatalog(t, tag, "Project", "Arbeitsbereich")))
+	tr.SetTenantCatalog("globex", NewCatalogProvider(newTestCatalog(t, tag, "Project", "Vorhaben")))
+
+	acme := WithTenant(context.Background(), "acme")
+	globex := WithTenant(context.Background(), "globex")
+
+	t.Run("Overrides of the tenant", func(t *testing.T) {
+		assert.Equal(t, "Arbeitsbereich", tr.TContext(acme, tag, "Project"))
+		assert.Equal(t, "Vorhaben", tr.TContext(globex, tag, "Project"))
+	})
+
+	t.Run("Shared catalog", func(t *testing.T) {
+		assert.Equal(t, "Neu: x", tr.TContext(acme, tag, "New %s", "x"))
+		assert.Equal(t, "Projekt", tr.TContext(WithTenant(context.Background(), "initech"), tag, "Project"))
+		assert.Equal(t, "Projekt", tr.TContext(context.Background(), tag, "Project"))
+		assert.Equal(t, "Projekt", tr.T(tag, "Project"))
+	})
+
+	t.Run("Printers are isolated", func(t *testing.T) {
+		a := tr.view(acme).printer(tag, "Project").Printer
+		g := tr.view(globex).printer(tag, "Project").Printer
+		assert.NotSame(t, a, g)
+		assert.Same(t, a, tr.view(acme).printer(tag, "Project").Printer)
+	})
+
+	t.Run("Thtml", func(t *testing.T) {
+		assert.Equal(t, template.HTML("Arbeitsbereich"), tr.ThtmlContext(acme, tag, "Project"))
+	})
+
+	t.Run("FuncMap", func(t *testing.T) {
+		tmpl, err := template.New("").Funcs(tr.FuncMapContext(globex, DefaultFuncs...)).Parse(`{{ T .Lang "Project" }}`)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.Execute(&buf, map[string]any{"Lang": "x-tn"}))
+		assert.Equal(t, "Vorhaben", buf.String())
+	})
+
+	t.Run("Tenant func", func(t *testing.T) {
+		type orgKey struct{}
+		tr.SetTenantFunc(func(ctx context.Context) (string, bool) {
+			org, ok := ctx.Value(orgKey{}).(string)
+			return org, ok
+		})
+		t.Cleanup(func() { tr.SetTenantFunc(nil) })
+
+		ctx := context.WithValue(context.Background(), orgKey{}, "globex")
+		assert.Equal(t, "Vorhaben", tr.TContext(ctx, tag, "Project"))
+	})
+
+	t.Run("Removed tenant", func(t *testing.T) {
+		tr.SetTenantCatalog("acme", nil)
+		assert.Equal(t, "Projekt", tr.TContext(acme, tag, "Project"))
+	})
+}
+
+func TestTContext(t *testing.T) {
+	tag := language.MustParse("x-tc")
+	SetTenantCatalog("acme", NewCatalogProvider(newTestCatalog(t, tag, "Project", "Workspace")))
+	t.Cleanup(func() { SetTenantCatalog("acme", nil) })
+
+	ctx := WithTenant(context.Background(), "acme")
+	assert.Equal(t, "Workspace", TContext(ctx, tag, "Project"))
+	assert.Equal(t, template.HTML("Workspace"), ThtmlContext(ctx, tag, "Project"))
+	assert.Equal(t, "Project", TContext(context.Background(), tag, "Project"))
+
+	tmpl, err := template.New("").Funcs(NewFuncMapContext(ctx, DefaultFuncs...)).Parse(`{{ T .Lang "Project" }}`)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, map[string]any{"Lang": "x-tc"}))
+	assert.Equal(t, "Workspace", buf.String())
+}
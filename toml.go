@@ -0,0 +1,282 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// reBareKey matches TOML keys that need no quotes.
+var reBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// TOMLCodec is a TOML table of message keys to translations. Tables and
+// dotted keys are flattened to dotted keys, a table of plural cases is a
+// plural message and the comment lines above a key are its comment:
+//
+//	# shown on the checkout page
+//	checkout.pay = "Jetzt bezahlen"
+//	"%d items" = { one = "ein Artikel", other = "%d Artikel" }
+type TOMLCodec struct{}
+
+func (TOMLCodec) Decode(data []byte) (*TranslationFile, error) {
+	var values map[string]any
+	md, err := toml.Decode(string(data), &values)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := tomlComments(data)
+	f := &TranslationFile{}
+
+	var plural toml.Key // key of the last plural message, whose cases are skipped
+	for _, k := range md.Keys() {
+		if plural != nil && len(k) == len(plural)+1 && slices.Equal(k[:len(plural)], plural) {
+			continue
+		}
+
+		key := strings.Join(k, ".")
+		switch v := tomlValue(values, k).(type) {
+		case string:
+			f.Messages = append(f.Messages, keyedTranslation(key, Text{Msg: v}, comments[key]))
+		case map[string]any:
+			cases, ok := tomlPlural(v)
+			if !ok {
+				continue
+			}
+			plural = k
+			f.Messages = append(f.Messages, keyedTranslation(key, Text{Cases: cases}, comments[key]))
+		default:
+			return nil, fmt.Errorf("%q: expected a message, plural cases or nested messages", key)
+		}
+	}
+	return f, nil
+}
+
+func (TOMLCodec) Encode(f *TranslationFile) ([]byte, error) {
+	var (
+		buf      bytes.Buffer
+		messages = make(map[string]bool) // dotted keys, true for messages and false for tables
+	)
+
+	for _, m := range f.Messages {
+		key := translationKey(m)
+
+		path := keyPath(key)
+		if !defineTOML(messages, path) {
+			path = []string{key}
+			if !defineTOML(messages, path) {
+				return nil, fmt.Errorf("message %q conflicts with another message", key)
+			}
+		}
+
+		if m.Comment != "" {
+			buf.WriteString(commentText(m.Comment))
+			buf.WriteByte('\n')
+		}
+
+		keys := make([]string, len(path))
+		for i, name := range path {
+			keys[i] = tomlKey(name)
+		}
+		fmt.Fprintf(&buf, "%s = %s\n", strings.Join(keys, "."), tomlText(m.Translation))
+	}
+	return buf.Bytes(), nil
+}
+
+// defineTOML records path as a message in messages, unless a message or a
+// table is already defined there.
+func defineTOML(messages map[string]bool, path []string) bool {
+	if _, ok := messages[strings.Join(path, "\x00")]; ok {
+		return false
+	}
+	for i := 1; i < len(path); i++ {
+		if messages[strings.Join(path[:i], "\x00")] {
+			return false
+		}
+	}
+
+	for i := 1; i < len(path); i++ {
+		messages[strings.Join(path[:i], "\x00")] = false
+	}
+	messages[strings.Join(path, "\x00")] = true
+	return true
+}
+
+// tomlValue returns the value at the key path k of values.
+func tomlValue(values map[string]any, k []string) any {
+	var v any = values
+	for _, name := range k {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[name]
+	}
+	return v
+}
+
+// tomlPlural returns the cases of a table of plural cases.
+func tomlPlural(table map[string]any) (map[string]string, bool) {
+	cases := make(map[string]string, len(table))
+	keys := make([]string, 0, len(table))
+	for k, v := range table {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		cases[k] = s
+		keys = append(keys, k)
+	}
+	return cases, isPlural(keys)
+}
+
+// tomlComments returns the comment lines right above each key or table
+// header of a TOML document by dotted key. Multi-line strings are skipped.
+func tomlComments(data []byte) map[string]string {
+	var (
+		comments  = make(map[string]string)
+		table     []string
+		pending   []string
+		multiline string // delimiter of the multi-line string being skipped
+	)
+
+	for line := range strings.SplitSeq(string(data), "\n") {
+		line = strings.TrimSpace(line)
+
+		if multiline != "" {
+			if strings.Contains(line, multiline) {
+				multiline = ""
+			}
+			continue
+		}
+
+		switch {
+		case line == "":
+			pending = nil
+		case strings.HasPrefix(line, "#"):
+			pending = append(pending, commentLines(line))
+		case strings.HasPrefix(line, "["):
+			header := strings.TrimSpace(strings.Trim(line, "[]"))
+			if keys, _, ok := parseTOMLKey(header); ok {
+				table = keys
+				if len(pending) > 0 {
+					comments[strings.Join(table, ".")] = strings.Join(pending, "\n")
+				}
+			}
+			pending = nil
+		default:
+			keys, rest, ok := parseTOMLKey(line)
+			if !ok {
+				pending = nil
+				continue
+			}
+
+			if len(pending) > 0 {
+				full := append(append([]string{}, table...), keys...)
+				comments[strings.Join(full, ".")] = strings.Join(pending, "\n")
+			}
+			pending = nil
+
+			value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), "="))
+			for _, delim := range []string{`"""`, `'''`} {
+				if strings.HasPrefix(value, delim) && !strings.Contains(value[len(delim):], delim) {
+					multiline = delim
+				}
+			}
+		}
+	}
+	return comments
+}
+
+// parseTOMLKey parses the possibly dotted and quoted key at the start of s
+// and returns the rest of s.
+func parseTOMLKey(s string) (keys []string, rest string, ok bool) {
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return nil, "", false
+		}
+
+		var key string
+		switch s[0] {
+		case '"':
+			end := 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, "", false
+			}
+			unquoted, err := strconv.Unquote(s[:end+1])
+			if err != nil {
+				return nil, "", false
+			}
+			key, s = unquoted, s[end+1:]
+		case '\'':
+			end := strings.IndexByte(s[1:], '\'')
+			if end < 0 {
+				return nil, "", false
+			}
+			key, s = s[1:end+1], s[end+2:]
+		default:
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return !(r == '_' || r == '-' || r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z')
+			})
+			if end == 0 {
+				return nil, "", false
+			}
+			if end < 0 {
+				end = len(s)
+			}
+			key, s = s[:end], s[end:]
+		}
+		keys = append(keys, key)
+
+		s = strings.TrimLeft(s, " \t")
+		if !strings.HasPrefix(s, ".") {
+			return keys, s, true
+		}
+		s = s[1:]
+	}
+}
+
+// tomlKey returns name as a TOML key, quoted if needed.
+func tomlKey(name string) string {
+	if reBareKey.MatchString(name) {
+		return name
+	}
+	return tomlString(name)
+}
+
+// tomlText returns a plain text as a string and a plural text as an inline
+// table of its cases.
+func tomlText(t Text) string {
+	if t.Cases == nil {
+		return tomlString(t.Msg)
+	}
+
+	cases := make([]string, 0, len(t.Cases))
+	for _, c := range pluralCases(t.Cases) {
+		cases = append(cases, tomlKey(c)+" = "+tomlString(t.Cases[c]))
+	}
+	return "{ " + strings.Join(cases, ", ") + " }"
+}
+
+// tomlString returns s as a TOML basic string. JSON escapes are valid TOML
+// escapes.
+func tomlString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,285 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// TranslationFile is a per-locale translation catalog in the gotext JSON
+// layout (messages.gotext.json, out.gotext.json).
+type TranslationFile struct {
+	Language string         `json:"language"`
+	Messages []*Translation `json:"messages"`
+}
+
+// Translation is a single message of a TranslationFile.
+type Translation struct {
+	ID                string          `json:"id"`
+	Key               string          `json:"key,omitempty"`
+	Message           Text            `json:"message"`
+	Translation       Text            `json:"translation"`
+	Comment           string          `json:"comment,omitempty"`
+	TranslatorComment string          `json:"translatorComment,omitempty"`
+	Placeholders      json.RawMessage `json:"placeholders,omitempty"` // gotext placeholders, kept verbatim
+	Fuzzy             bool            `json:"fuzzy,omitempty"`
+	Position          string          `json:"position,omitempty"`
+}
+
+// Text is a message text: either a plain string or, for plural messages, a
+// set of cases keyed by plural category ("one", "other", ...) selected by Arg.
+type Text struct {
+	Msg   string
+	Arg   string
+	Cases map[string]string
+}
+
+type (
+	jsonSelect struct {
+		Feature string                   `json:"feature"`
+		Arg     string                   `json:"arg"`
+		Cases   map[string]jsonSelectMsg `json:"cases"`
+	}
+	jsonSelectMsg struct {
+		Msg string `json:"msg"`
+	}
+)
+
+// ReadTranslationFile reads a translation file in the format of its
+// extension, see CodecFor; other files are read as gotext JSON. Files
+// without a language take it from their name, see Codec.
+func ReadTranslationFile(path string) (*TranslationFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTranslationFile(path, raw)
+}
+
+// WriteTranslationFile writes f to path in the format of its extension.
+func WriteTranslationFile(path string, f *TranslationFile) error {
+	codec, ok := CodecFor(path)
+	if !ok {
+		return fmt.Errorf("%s: unknown translation file format", path)
+	}
+
+	raw, err := codec.Encode(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return os.WriteFile(path, raw, 0644)
+}
+
+// ReadTranslationDir reads every translation file in dir and its
+// subdirectories: *.gotext.json, *.yaml, *.yml and *.toml.
+func ReadTranslationDir(dir string) ([]*TranslationFile, error) {
+	var files []*TranslationFile
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if _, ok := CodecFor(d.Name()); d.IsDir() || !ok {
+			return nil
+		}
+
+		f, err := ReadTranslationFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+
+		return nil
+	})
+
+	return files, err
+}
+
+// ReadTranslationFS is like ReadTranslationDir for a directory of fsys.
+func ReadTranslationFS(fsys fs.FS, dir string) ([]*TranslationFile, error) {
+	return readTranslationFS(fsys, dir, func(string) bool { return true })
+}
+
+// readTranslationFS reads the translation files in dir of fsys whose path
+// is accepted by keep.
+func readTranslationFS(fsys fs.FS, dir string, keep func(path string) bool) ([]*TranslationFile, error) {
+	var files []*TranslationFile
+
+	err := fs.WalkDir(fsys, dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if _, ok := CodecFor(d.Name()); d.IsDir() || !ok || !keep(path) {
+			return nil
+		}
+
+		raw, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := decodeTranslationFile(path, raw)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+
+		return nil
+	})
+
+	return files, err
+}
+
+func decodeTranslationFile(path string, raw []byte) (*TranslationFile, error) {
+	codec, ok := CodecFor(path)
+	if !ok {
+		codec = GotextCodec{}
+	}
+
+	f, err := codec.Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if f.Language == "" {
+		f.Language = pathLanguage(path)
+	}
+	return f, nil
+}
+
+// Validate reports every translation that does not use the same named
+// placeholders as its source message. Untranslated messages and messages
+// without a known source text, such as keyed messages read from YAML, are
+// skipped.
+func (f *TranslationFile) Validate() error {
+	var errs []error
+	for _, m := range f.Messages {
+		source := m.Message.String()
+		if source == "" {
+			if reKey.MatchString(m.ID) {
+				continue
+			}
+			source = m.ID
+		}
+
+		for _, translation := range m.Translation.Texts() {
+			if translation == "" {
+				continue
+			}
+			if err := ValidatePlaceholders(source, translation); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q: %w", f.Language, m.ID, err))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Translation) UnmarshalJSON(data []byte) error {
+	type alias Translation
+	aux := struct {
+		ID json.RawMessage `json:"id"`
+		*alias
+	}{alias: (*alias)(m)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// gotext writes a single ID as a string and alternatives as a list
+	if len(aux.ID) > 0 && aux.ID[0] == '[' {
+		var ids []string
+		if err := json.Unmarshal(aux.ID, &ids); err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			m.ID = ids[0]
+		}
+		return nil
+	}
+	return json.Unmarshal(aux.ID, &m.ID)
+}
+
+// String returns the plain text, or the "other" case of a plural message.
+func (t Text) String() string {
+	if t.Cases == nil {
+		return t.Msg
+	}
+	return t.Cases["other"]
+}
+
+// Texts returns every text variant: the plain text or all plural cases.
+func (t Text) Texts() []string {
+	if t.Cases == nil {
+		return []string{t.Msg}
+	}
+	return slices.Collect(maps.Values(t.Cases))
+}
+
+// replace returns t with fn applied to the plain text or every plural case.
+func (t Text) replace(fn func(string) string) Text {
+	if t.Cases == nil {
+		return Text{Msg: fn(t.Msg)}
+	}
+
+	cases := make(map[string]string, len(t.Cases))
+	for c, msg := range t.Cases {
+		cases[c] = fn(msg)
+	}
+	return Text{Arg: t.Arg, Cases: cases}
+}
+
+func (t Text) MarshalJSON() ([]byte, error) {
+	if t.Cases == nil {
+		return json.Marshal(t.Msg)
+	}
+
+	cases := make(map[string]jsonSelectMsg, len(t.Cases))
+	for k, v := range t.Cases {
+		cases[k] = jsonSelectMsg{Msg: v}
+	}
+	return json.Marshal(struct {
+		Select jsonSelect `json:"select"`
+	}{jsonSelect{Feature: "plural", Arg: t.Arg, Cases: cases}})
+}
+
+func (t *Text) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || data[0] != '{' {
+		*t = Text{}
+		return json.Unmarshal(data, &t.Msg)
+	}
+
+	var aux struct {
+		Msg    string `json:"msg"`
+		Select *struct {
+			Arg   string                     `json:"arg"`
+			Cases map[string]json.RawMessage `json:"cases"`
+		} `json:"select"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*t = Text{Msg: aux.Msg}
+	if aux.Select == nil {
+		return nil
+	}
+
+	t.Arg = aux.Select.Arg
+	t.Cases = make(map[string]string, len(aux.Select.Cases))
+	for k, raw := range aux.Select.Cases {
+		var c Text
+		if err := c.UnmarshalJSON(raw); err != nil {
+			return err
+		}
+		t.Cases[k] = c.Msg
+	}
+	return nil
+}
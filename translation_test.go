@@ -0,0 +1,159 @@
+package i18n
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gotextFile = `{
+    "language": "de-DE",
+    "messages": [
+        {
+            "id": "Hello {name}",
+            "message": "Hello {name}",
+            "translation": "Hallo {name}"
+        },
+        {
+            "id": ["{count} messages", "msg-count"],
+            "message": "{count} messages",
+            "translation": {
+                "select": {
+                    "feature": "plural",
+                    "arg": "count",
+                    "cases": {
+                        "one": {"msg": "{count} Nachricht"},
+                        "other": "{count} Nachrichten"
+                    }
+                }
+            },
+            "fuzzy": true
+        },
+        {
+            "id": "Untranslated {name}",
+            "message": "Untranslated {name}",
+            "translation": ""
+        }
+    ]
+}`
+
+func TestReadTranslationFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.gotext.json")
+	require.NoError(t, os.WriteFile(path, []byte(gotextFile), 0644))
+
+	f, err := ReadTranslationFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "de-DE", f.Language)
+	require.Len(t, f.Messages, 3)
+
+	assert.Equal(t, "Hello {name}", f.Messages[0].ID)
+	assert.Equal(t, Text{Msg: "Hallo {name}"}, f.Messages[0].Translation)
+
+	assert.Equal(t, "{count} messages", f.Messages[1].ID)
+	assert.True(t, f.Messages[1].Fuzzy)
+	assert.Equal(t, Text{Arg: "count", Cases: map[string]string{
+		"one":   "{count} Nachricht",
+		"other": "{count} Nachrichten",
+	}}, f.Messages[1].Translation)
+	assert.Equal(t, "{count} Nachrichten", f.Messages[1].Translation.String())
+
+	assert.NoError(t, f.Validate())
+}
+
+func TestReadTranslationFileError(t *testing.T) {
+	_, err := ReadTranslationFile("/nonexistent/messages.gotext.json")
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "broken.gotext.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"messages": [`), 0644))
+
+	_, err = ReadTranslationFile(path)
+	assert.ErrorContains(t, err, path)
+}
+
+func TestReadTranslationDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "de-DE"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "de-DE", "messages.gotext.json"), []byte(gotextFile), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.json"), []byte("not json"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fr.yaml"), []byte("Hello {name}: Bonjour {name}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "messages.it.toml"), []byte(`"Hello {name}" = "Ciao {name}"`), 0644))
+
+	files, err := ReadTranslationDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	assert.Equal(t, "de-DE", files[0].Language)
+	assert.Equal(t, "fr", files[1].Language)
+	assert.Equal(t, []*Translation{{ID: "Hello {name}", Key: "Hello {name}", Translation: Text{Msg: "Bonjour {name}"}}}, files[1].Messages)
+	assert.Equal(t, "it", files[2].Language)
+
+	_, err = ReadTranslationDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestTranslationFileValidate(t *testing.T) {
+	f := &TranslationFile{
+		Language: "it-IT",
+		Messages: []*Translation{
+			{ID: "Hello {name}", Translation: Text{Msg: "Ciao {nome}"}},
+			{ID: "{count} items", Message: Text{Msg: "{count} items"}, Translation: Text{Cases: map[string]string{
+				"one":   "un elemento",
+				"other": "{count} elementi",
+			}}},
+			{ID: "Bye {name}", Translation: Text{Msg: "Ciao {name}"}},
+			{ID: "checkout.pay", Key: "checkout.pay", Translation: Text{Msg: "Paga {amount}"}},
+		},
+	}
+
+	err := f.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `it-IT: "Hello {name}": missing placeholders {name}, unknown placeholders {nome}`)
+	assert.Contains(t, err.Error(), `it-IT: "{count} items": missing placeholders {count}`)
+	assert.NotContains(t, err.Error(), "Bye")
+	assert.NotContains(t, err.Error(), "checkout.pay")
+}
+
+func TestWriteTranslationFile(t *testing.T) {
+	dir := t.TempDir()
+	f := &TranslationFile{Language: "de", Messages: []*Translation{{ID: "Hello {name}", Translation: Text{Msg: "Hallo {name}"}}}}
+
+	for _, name := range []string{"de.yaml", "de.toml", "messages.gotext.json"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, WriteTranslationFile(path, f))
+
+		read, err := ReadTranslationFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "de", read.Language)
+		assert.Equal(t, "Hallo {name}", read.Messages[0].Translation.Msg)
+	}
+
+	assert.ErrorContains(t, WriteTranslationFile(filepath.Join(dir, "de.txt"), f), "unknown translation file format")
+}
+
+func TestTextJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		text Text
+		json string
+	}{
+		{"plain", Text{Msg: "Hello"}, `"Hello"`},
+		{"plural", Text{Arg: "n", Cases: map[string]string{"one": "1 item", "other": "%d items"}}, `{"select":{"feature":"plural","arg":"n","cases":{"one":{"msg":"1 item"},"other":{"msg":"%d items"}}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(tt.text)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(raw))
+
+			var text Text
+			require.NoError(t, json.Unmarshal(raw, &text))
+			assert.Equal(t, tt.text, text)
+		})
+	}
+}
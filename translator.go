@@ -0,0 +1,188 @@
+package i18n
+
+import (
+	"context"
+	"html/template"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// std is the Translator of T, Thtml and FuncMap.
+var std = NewTranslator(nil)
+
+// Translator translates messages with a CatalogProvider. Messages the
+// provider does not have are translated with Printer, or with the
+// translations of libraries for messages the active catalog lacks.
+type Translator struct {
+	provider atomic.Pointer[CatalogProvider]
+	tenants  sync.Map // of CatalogProvider by tenant
+	domains  sync.Map // of *domain by name
+	tenantFn atomic.Pointer[func(context.Context) (string, bool)]
+
+	libraries   atomic.Pointer[[]library] // in registration order
+	librariesMu sync.Mutex
+}
+
+// NewTranslator returns a Translator consulting provider, which may be nil.
+func NewTranslator(provider CatalogProvider) *Translator {
+	tr := &Translator{}
+	tr.SetProvider(provider)
+	return tr
+}
+
+// SetCatalog sets the provider consulted by T, Thtml and FuncMap before
+// Printer, e.g. Layered{tenant, product, defaults}.
+func SetCatalog(provider CatalogProvider) {
+	std.SetProvider(provider)
+}
+
+// SetProvider replaces the provider of tr.
+func (tr *Translator) SetProvider(provider CatalogProvider) {
+	tr.provider.Store(&provider)
+}
+
+// Printer returns the printer of the provider that has key in tag, or
+// Printer(tag) if the active catalog has it, or else the printer of the
+// first library that has it, or Printer(tag).
+func (tr *Translator) Printer(tag language.Tag, key string) *message.Printer {
+	return tr.printer(tag, key).Printer
+}
+
+// printer is like Printer, returning the catalog of the printer too.
+func (tr *Translator) printer(tag language.Tag, key string) catalogPrinter {
+	if p := tr.provider.Load(); p != nil && *p != nil && key != "" {
+		if printer, ok := providerPrinter(*p, tag, key); ok {
+			return printer
+		}
+	}
+	if printer, ok := tr.libraryPrinter(tag, key); ok {
+		return printer
+	}
+	return printerOf(tag)
+}
+
+// Lookup is like the package function Lookup, using the provider of tr.
+func (tr *Translator) Lookup(tag language.Tag, key string) (string, bool) {
+	if p := tr.provider.Load(); p != nil && *p != nil && key != "" {
+		if printer, ok := providerPrinter(*p, tag, key); ok {
+			return printer.text(key, key, key), true
+		}
+	}
+	if text, ok := appLookup(tag, key); ok {
+		return text, true
+	}
+	if printer, ok := tr.libraryPrinter(tag, key); ok {
+		return printer.text(key, key, key), true
+	}
+	return "", false
+}
+
+// Keys is like the package function Keys, using the provider of tr.
+func (tr *Translator) Keys(tag language.Tag) iter.Seq[string] {
+	found := make(map[string]struct{})
+	if p := tr.provider.Load(); p != nil && *p != nil {
+		addKeys(found, *p, tag)
+	}
+	for _, key := range keys.list(tag) {
+		found[key] = struct{}{}
+	}
+	if app := printerOf(tag); app.cat != nil {
+		for _, key := range keys.list(app.tag) {
+			found[key] = struct{}{}
+		}
+		addKeys(found, app.cat, app.tag)
+	}
+	if list := tr.libraries.Load(); list != nil {
+		for _, l := range *list {
+			addKeys(found, l.provider, tag)
+		}
+	}
+
+	var list []string
+	for key := range found {
+		if _, ok := tr.Lookup(tag, key); ok {
+			list = append(list, key)
+		}
+	}
+	slices.Sort(list)
+	return slices.Values(list)
+}
+
+// T is like the package function T, using the provider of tr.
+func (tr *Translator) T(tag language.Tag, key message.Reference, a ...any) string {
+	return view{tr: tr}.t(tag, key, a...)
+}
+
+// Thtml is like the package function Thtml, using the provider of tr.
+func (tr *Translator) Thtml(tag language.Tag, key message.Reference, a ...any) template.HTML {
+	return view{tr: tr}.thtml(tag, key, a...)
+}
+
+// FuncMap returns a template.FuncMap like NewFuncMap whose translation
+// functions use tr.
+func (tr *Translator) FuncMap(funcs ...Func) template.FuncMap {
+	return view{tr: tr}.funcMap(funcs)
+}
+
+func (tr *Translator) trans(lang any, key message.Reference, a ...any) string {
+	return view{tr: tr}.trans(lang, key, a...)
+}
+
+func (tr *Translator) transHTML(lang any, key message.Reference, a ...any) template.HTML {
+	return view{tr: tr}.transHTML(lang, key, a...)
+}
+
+// view translates with the overrides of a tenant, if any, then the catalog
+// of a domain, if any, then like tr.
+type view struct {
+	tr     *Translator
+	tenant string
+	domain string
+}
+
+func (v view) printer(tag language.Tag, key string) catalogPrinter {
+	if v.tenant != "" && key != "" {
+		if p, ok := v.tr.tenants.Load(v.tenant); ok {
+			if printer, ok := providerPrinter(p.(CatalogProvider), tag, key); ok {
+				return printer
+			}
+		}
+	}
+	if v.domain != "" && key != "" {
+		if printer, ok := v.tr.domainPrinter(v.domain, tag, key); ok {
+			return printer
+		}
+	}
+	return v.tr.printer(tag, key)
+}
+
+func (v view) t(tag language.Tag, key message.Reference, a ...any) string {
+	key, id, source := reference(key)
+	return sprintf(v.printer(tag, id), key, id, source, a)
+}
+
+func (v view) thtml(tag language.Tag, key message.Reference, a ...any) template.HTML {
+	key, id, source := reference(key)
+	return template.HTML(sprintf(v.printer(tag, id), key, id, source, escapeArgs(a)))
+}
+
+func (v view) trans(lang any, key message.Reference, a ...any) string {
+	return v.t(langTag(lang), key, a...)
+}
+
+func (v view) transHTML(lang any, key message.Reference, a ...any) template.HTML {
+	return v.thtml(langTag(lang), key, a...)
+}
+
+func (v view) funcMap(funcs []Func) template.FuncMap {
+	fm := template.FuncMap{"L": language.MustParse, "Key": Key}
+	for _, f := range funcs {
+		fm[f.Name] = f.fn(v)
+	}
+	return fm
+}
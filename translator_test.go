@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestTranslator(t *testing.T) {
+	tag := language.MustParse("x-tr")
+	tr := NewTranslator(Layered{
+		NewCatalogProvider(newTestCatalog(t, tag, "Hello %s", "Servus %s", "<b>{name}</b>", "<i>{name}</i>")),
+		NewCatalogProvider(newTestCatalog(t, tag, "Hello %s", "Hallo %s", "Bye", "Tschüss", "checkout.pay", "Bezahlen")),
+	})
+
+	t.Run("T", func(t *testing.T) {
+		assert.Equal(t, "Servus Bob", tr.T(tag, "Hello %s", "Bob"))
+		assert.Equal(t, "Tschüss", tr.T(tag, "Bye"))
+		assert.Equal(t, "Bezahlen", tr.T(tag, Key("checkout.pay", "Pay now")))
+		assert.Equal(t, "Missing", tr.T(tag, "Missing"))
+		assert.Equal(t, "Pay later", tr.T(tag, Key("checkout.later", "Pay later")))
+	})
+
+	t.Run("Thtml", func(t *testing.T) {
+		assert.Equal(t, template.HTML("<i>&lt;Bob&gt;</i>"), tr.Thtml(tag, "<b>{name}</b>", "name", "<Bob>"))
+	})
+
+	t.Run("FuncMap", func(t *testing.T) {
+		tmpl, err := template.New("").Funcs(tr.FuncMap(DefaultFuncs...)).Parse(`{{ T .Lang "Hello %s" .Name }}`)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.Execute(&buf, map[string]any{"Lang": "x-tr", "Name": "Bob"}))
+		assert.Equal(t, "Servus Bob", buf.String())
+	})
+
+	t.Run("Nil provider", func(t *testing.T) {
+		assert.Equal(t, "Hello Bob", NewTranslator(nil).T(tag, "Hello %s", "Bob"))
+	})
+}
+
+func TestSetCatalog(t *testing.T) {
+	t.Cleanup(func() { SetCatalog(nil) })
+
+	tag := language.MustParse("x-sc")
+	SetCatalog(NewCatalogProvider(newTestCatalog(t, tag, "Good morning", "Guten Morgen")))
+
+	assert.Equal(t, "Guten Morgen", T(tag, "Good morning"))
+
+	tmpl, err := template.New("").Funcs(FuncMap).Parse(`{{ T .Lang "Good morning" }}`)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, map[string]any{"Lang": "x-sc"}))
+	assert.Equal(t, "Guten Morgen", buf.String())
+
+	SetCatalog(nil)
+	assert.Equal(t, "Good morning", T(tag, "Good morning"))
+}
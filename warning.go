@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// rePragma matches the template comment that suppresses warnings, e.g.
+	// {{/* i18n:ignore */}}.
+	rePragma = regexp.MustCompile(`/\*\s*i18n:ignore\s*\*/`)
+
+	// reVerb matches a fmt verb including flags, argument index, width and
+	// precision.
+	reVerb = regexp.MustCompile(`%[-+# 0]*(\[\d+\])?(\d+|\*)?(\.(\d+|\*)?)?(\[\d+\])?[a-zA-Z%]`)
+)
+
+// pragmaLines returns the lines whose warnings are suppressed: the line of
+// every i18n:ignore comment and the line after it.
+func pragmaLines(content []byte) map[int]struct{} {
+	lines := make(map[int]struct{})
+	for _, loc := range rePragma.FindAllIndex(content, -1) {
+		line := lineOf(content, loc[0])
+		lines[line] = struct{}{}
+		lines[line+1] = struct{}{}
+	}
+	return lines
+}
+
+// lineOf returns the 1-based line of the byte offset pos.
+func lineOf(content []byte, pos int) int {
+	return bytes.Count(content[:pos], []byte("\n")) + 1
+}
+
+// keyWarning describes a message key argument that is not a string literal.
+func keyWarning(arg string) string {
+	if strings.HasPrefix(arg, "(") && strings.ContainsAny(arg, "\"`") {
+		return fmt.Sprintf("message key %s is concatenated at runtime and cannot be extracted", arg)
+	}
+	return fmt.Sprintf("dynamic message key %s cannot be extracted", arg)
+}
+
+// formatWarning reports a message whose fmt verbs do not match the nargs
+// arguments it is called with. Messages with named placeholders are not
+// Printf formats, so a % in them is not a verb.
+func formatWarning(source string, nargs int) string {
+	if len(Placeholders(source)) > 0 {
+		return ""
+	}
+
+	var (
+		verbs   int
+		indexed bool
+	)
+	for _, verb := range reVerb.FindAllString(source, -1) {
+		if verb == "%%" {
+			continue
+		}
+		verbs++
+		indexed = indexed || strings.Contains(verb, "[")
+	}
+
+	if !indexed && verbs != nargs {
+		return fmt.Sprintf("message %q has %d format verbs but %d arguments", source, verbs, nargs)
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatWarning(t *testing.T) {
+	tests := []struct {
+		source   string
+		nargs    int
+		expected string
+	}{
+		{"Hello", 0, ""},
+		{"Hello %s", 1, ""},
+		{"%d of %d", 2, ""},
+		{"100%% sure", 0, ""},
+		{"%[2]s %[1]s", 1, ""},
+		{"Hello {name}", 1, ""},
+		{"Hello %s", 0, `message "Hello %s" has 1 format verbs but 0 arguments`},
+		{"50% off", 0, `message "50% off" has 1 format verbs but 0 arguments`},
+		{"Hello", 1, `message "Hello" has 0 format verbs but 1 arguments`},
+		{"50% off for {name}", 2, ""},
+		{"{count}% done", 2, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatWarning(tt.source, tt.nargs))
+		})
+	}
+}
+
+func TestKeyWarning(t *testing.T) {
+	assert.Equal(t, "dynamic message key .Key cannot be extracted", keyWarning(".Key"))
+	assert.Equal(t, `message key (print "shop." .Name) is concatenated at runtime and cannot be extracted`, keyWarning(`(print "shop." .Name)`))
+}
+
+func TestPragmaLines(t *testing.T) {
+	content := []byte("a\n{{/* i18n:ignore */}}\nb\nc\n{{ T .Lang .X }} {{/*i18n:ignore*/}}")
+	assert.Equal(t, map[int]struct{}{2: {}, 3: {}, 5: {}, 6: {}}, pragmaLines(content))
+}
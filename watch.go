@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// Watch runs Extract, then polls the scanned directory every interval and
+// runs it again whenever a template is added, removed or modified, until ctx
+// is done. Errors after the first run are reported on the log writer and
+// watching continues.
+func (e *Extractor) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last string
+	for first := true; ; first = false {
+		snapshot, err := e.snapshot()
+		switch {
+		case err != nil && first:
+			return fmt.Errorf("extract error: %w", err)
+		case err != nil:
+			e.logf("%v\n", err)
+		case snapshot != last:
+			last = snapshot
+			if err := e.Extract(); err != nil {
+				if first {
+					return err
+				}
+				e.logf("%v\n", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshot hashes the paths, sizes and modification times of all templates.
+func (e *Extractor) snapshot() (string, error) {
+	files, err := e.templates()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, f := range files {
+		info, err := fs.Stat(f.fsys, f.path)
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\n", f.rel, info.Size(), info.ModTime().UnixNano())
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "messages.json")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "page.html"), []byte(`{{ T .Lang "First" }}`), 0644))
+
+	extractor := NewExtractor(WithDir(dir), WithOut(out), WithGoFile(filepath.Join(dir, "stub.go")), WithExts(".html"))
+
+	outContains := func(s string) func() bool {
+		return func() bool {
+			raw, _ := os.ReadFile(out)
+			return bytes.Contains(raw, []byte(s))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- extractor.Watch(ctx, 10*time.Millisecond)
+	}()
+
+	assert.Eventually(t, outContains("First"), 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.html"), []byte(`{{ T .Lang "Second" }}`), 0644))
+	assert.Eventually(t, outContains("Second"), 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch did not stop")
+	}
+}
+
+func TestWatchError(t *testing.T) {
+	extractor := NewExtractor(WithDir("/nonexistent/directory"))
+
+	err := extractor.Watch(context.Background(), time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestWatchLogsErrors(t *testing.T) {
+	dir := t.TempDir()
+	templates := filepath.Join(dir, "templates")
+	require.NoError(t, os.Mkdir(templates, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(templates, "page.html"), []byte(`{{ T .Lang "First" }}`), 0644))
+
+	var log lockedBuffer
+	extractor := NewExtractor(WithDir(templates), WithOut(filepath.Join(dir, "messages.json")), WithGoFile(""), WithExts(".html"), WithLog(&log))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- extractor.Watch(ctx, 10*time.Millisecond)
+	}()
+
+	assert.Eventually(t, func() bool { return strings.Contains(log.String(), "Wrote 1 messages") }, 2*time.Second, 10*time.Millisecond)
+	require.NoError(t, os.RemoveAll(templates))
+	assert.Eventually(t, func() bool { return strings.Contains(log.String(), "no such file or directory") }, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
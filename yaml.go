@@ -0,0 +1,242 @@
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLCodec is a YAML mapping of message keys to translations. Nested
+// mappings are flattened to dotted keys, a mapping of plural cases is a
+// plural message and the comments above a key are its comment. Comments
+// above a nested mapping are part of the comments of all its messages:
+//
+//	# shown on the checkout page
+//	checkout:
+//	  pay: Jetzt bezahlen
+//	"%d items":
+//	  one: ein Artikel
+//	  other: "%d Artikel"
+type YAMLCodec struct{}
+
+func (YAMLCodec) Decode(data []byte) (*TranslationFile, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	f := &TranslationFile{}
+	if len(doc.Content) == 0 {
+		return f, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of messages", root.Line)
+	}
+	if err := decodeYAML(f, root, "", ""); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (YAMLCodec) Encode(f *TranslationFile) ([]byte, error) {
+	var (
+		root     = &yaml.Node{Kind: yaml.MappingNode}
+		comments = make(map[*yaml.Node]string) // of messages by key node
+	)
+	for _, m := range f.Messages {
+		key := translationKey(m)
+		value := yamlText(m.Translation)
+
+		n, ok := insertYAML(root, keyPath(key), value)
+		if !ok {
+			n, ok = insertYAML(root, []string{key}, value)
+		}
+		if !ok {
+			return nil, fmt.Errorf("message %q conflicts with another message", key)
+		}
+		comments[n] = m.Comment
+	}
+	commentYAML(root, comments, "")
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeYAML adds the messages of mapping n to f, prefixing their keys and
+// comments.
+func decodeYAML(f *TranslationFile, n *yaml.Node, prefix, parentComment string) error {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if v.Kind == yaml.AliasNode {
+			v = v.Alias
+		}
+
+		key := k.Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		comment := joinComments(parentComment, commentLines(k.HeadComment+"\n"+v.LineComment))
+
+		switch {
+		case v.Kind == yaml.ScalarNode:
+			f.Messages = append(f.Messages, keyedTranslation(key, Text{Msg: v.Value}, comment))
+		case isPluralYAML(v):
+			cases := make(map[string]string, len(v.Content)/2)
+			for j := 0; j+1 < len(v.Content); j += 2 {
+				cases[v.Content[j].Value] = v.Content[j+1].Value
+			}
+			f.Messages = append(f.Messages, keyedTranslation(key, Text{Cases: cases}, comment))
+		case v.Kind == yaml.MappingNode:
+			if err := decodeYAML(f, v, key, comment); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("line %d: %q: expected a message, plural cases or nested messages", v.Line, key)
+		}
+	}
+	return nil
+}
+
+// isPluralYAML reports whether n is a mapping of plural cases to messages.
+func isPluralYAML(n *yaml.Node) bool {
+	if n.Kind != yaml.MappingNode || len(n.Content) == 0 {
+		return false
+	}
+
+	keys := make([]string, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i+1].Kind != yaml.ScalarNode {
+			return false
+		}
+		keys = append(keys, n.Content[i].Value)
+	}
+	return isPlural(keys)
+}
+
+// insertYAML adds value at path below mapping n and returns its key node,
+// unless a message or a mapping is already in the way.
+func insertYAML(n *yaml.Node, path []string, value *yaml.Node) (*yaml.Node, bool) {
+	for i, name := range path {
+		var child *yaml.Node
+		for j := 0; j+1 < len(n.Content); j += 2 {
+			if n.Content[j].Value == name {
+				child = n.Content[j+1]
+			}
+		}
+
+		if i == len(path)-1 {
+			if child != nil {
+				return nil, false
+			}
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+			n.Content = append(n.Content, key, value)
+			return key, true
+		}
+
+		switch {
+		case child == nil:
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, child)
+		case child.Kind != yaml.MappingNode || isPluralYAML(child):
+			return nil, false
+		}
+		n = child
+	}
+	return nil, false
+}
+
+// commentYAML sets the comments of the messages below mapping n, without
+// the lines of prefix. The lines all messages of a nested mapping start with
+// go above its key, as decodeYAML reads them.
+func commentYAML(n *yaml.Node, comments map[*yaml.Node]string, prefix string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+
+		comment, ok := comments[k]
+		if !ok {
+			comment = commonComment(v, comments)
+			commentYAML(v, comments, comment)
+		}
+		k.HeadComment = commentText(trimComment(comment, prefix))
+	}
+}
+
+// commonComment returns the leading comment lines shared by all messages
+// below mapping n.
+func commonComment(n *yaml.Node, comments map[*yaml.Node]string) string {
+	var (
+		common []string
+		first  = true
+	)
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			comment, ok := comments[n.Content[i]]
+			if !ok {
+				walk(n.Content[i+1])
+				continue
+			}
+
+			lines := strings.Split(comment, "\n")
+			if first {
+				common, first = lines, false
+				continue
+			}
+			j := 0
+			for j < len(common) && j < len(lines) && common[j] == lines[j] {
+				j++
+			}
+			common = common[:j]
+		}
+	}
+	walk(n)
+
+	return strings.Join(common, "\n")
+}
+
+// joinComments returns the non-empty comments a and b on separate lines.
+func joinComments(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + "\n" + b
+}
+
+// trimComment returns comment without the leading lines of prefix.
+func trimComment(comment, prefix string) string {
+	if prefix == "" || comment == prefix {
+		return strings.TrimPrefix(comment, prefix)
+	}
+	return strings.TrimPrefix(comment, prefix+"\n")
+}
+
+// yamlText returns the node of a plain or plural text.
+func yamlText(t Text) *yaml.Node {
+	if t.Cases == nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t.Msg}
+	}
+
+	n := &yaml.Node{Kind: yaml.MappingNode}
+	for _, c := range pluralCases(t.Cases) {
+		n.Content = append(n.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: c},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t.Cases[c]},
+		)
+	}
+	return n
+}